@@ -0,0 +1,75 @@
+package dbscan
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// capturedColumns contains destinations and positions of the captured columns.
+type capturedColumns struct {
+	values    []reflect.Value
+	columns   []string
+	positions []int
+}
+
+// newCapturedColumns finds the captured columns in rows,
+// it returns rows that hide them from the element scanner.
+func newCapturedColumns(rows Rows, captures []capture) (*capturedColumns, Rows, error) {
+	if len(captures) == 0 {
+		return nil, rows, nil
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, fmt.Errorf("scany: get rows columns: %w", err)
+	}
+	cc := &capturedColumns{}
+	for _, c := range captures {
+		value, err := parseDestination(c.dst)
+		if err != nil {
+			return nil, nil, fmt.Errorf("scany: capture of column '%s': %w", c.column, err)
+		}
+		position := columnPosition(columns, c.column)
+		if position < 0 {
+			return nil, nil, fmt.Errorf("scany: captured column '%s' isn't found in rows", c.column)
+		}
+		cc.values = append(cc.values, value)
+		cc.columns = append(cc.columns, c.column)
+		cc.positions = append(cc.positions, position)
+	}
+	return cc, newHiddenColumnsRows(rows, cc.positions), nil
+}
+
+// scan scans the captured columns of the current row into their destinations.
+func (cc *capturedColumns) scan(api *API, rows Rows) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("scany: get rows columns: %w", err)
+	}
+	scans := discardScanTargets(len(columns))
+	var finishers []finishScanFunc
+	for i, value := range cc.values {
+		var finish finishScanFunc
+		scans[cc.positions[i]], finish = api.scanTarget(value, cc.columns[i], false /* nullable */)
+		if finish != nil {
+			finishers = append(finishers, finish)
+		}
+	}
+	if err := rows.Scan(scans...); err != nil {
+		return fmt.Errorf("scany: scan captured columns: %w", err)
+	}
+	for _, finish := range finishers {
+		if _, err := finish(); err != nil {
+			return fmt.Errorf("scany: scan captured columns: %w", err)
+		}
+	}
+	if api.normalizationEnabled() {
+		padded, err := api.paddedColumns(rows)
+		if err != nil {
+			return err
+		}
+		for i, value := range cc.values {
+			api.normalizeValue(value, isPaddedColumn(padded, cc.positions[i]))
+		}
+	}
+	return nil
+}
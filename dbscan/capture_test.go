@@ -0,0 +1,103 @@
+package dbscan_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/georgysavva/scany/v2/dbscan"
+)
+
+const capturedRowsQuery = `
+	SELECT *, COUNT(*) OVER () AS total_count
+	FROM (
+		VALUES ('foo val', 'bar val'), ('foo val 2', 'bar val 2')
+	) AS t (foo, bar)
+`
+
+func TestScanAll_withCapture(t *testing.T) {
+	t.Parallel()
+	type dst struct {
+		Foo string
+		Bar string
+	}
+	expected := []dst{
+		{Foo: "foo val", Bar: "bar val"},
+		{Foo: "foo val 2", Bar: "bar val 2"},
+	}
+
+	var got []dst
+	var total int64
+	err := testAPI.ScanAll(&got, queryRows(t, capturedRowsQuery), dbscan.WithCapture("total_count", &total))
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+	assert.Equal(t, int64(2), total)
+}
+
+func TestScanAll_withCapture_keyedMap(t *testing.T) {
+	t.Parallel()
+	var got map[string]string
+	var total int64
+	err := testAPI.ScanAll(&got, queryRows(t, capturedRowsQuery),
+		dbscan.WithKeyColumn("foo"), dbscan.WithCapture("total_count", &total))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"foo val": "bar val", "foo val 2": "bar val 2"}, got)
+	assert.Equal(t, int64(2), total)
+}
+
+func TestScanAll_withCapture_noRows_leavesDestinationUntouched(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT *, COUNT(*) OVER () AS total_count
+		FROM (VALUES ('foo val', 'bar val')) AS t (foo, bar)
+		WHERE false
+	`
+	type dst struct {
+		Foo string
+		Bar string
+	}
+
+	var got []dst
+	total := int64(-1)
+	err := testAPI.ScanAll(&got, queryRows(t, query), dbscan.WithCapture("total_count", &total))
+	require.NoError(t, err)
+
+	assert.Empty(t, got)
+	assert.Equal(t, int64(-1), total)
+}
+
+func TestScanAll_withCapture_returnsErr(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name        string
+		column      string
+		dst         interface{}
+		expectedErr string
+	}{
+		{
+			name:        "absent column",
+			column:      "total",
+			dst:         new(int64),
+			expectedErr: "scany: captured column 'total' isn't found in rows",
+		},
+		{
+			name:   "non pointer destination",
+			column: "total_count",
+			dst:    int64(0),
+			expectedErr: "scany: capture of column 'total_count': " +
+				"scany: destination must be a pointer, got: int64",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var got []struct{ Foo, Bar string }
+			err := testAPI.ScanAll(&got, queryRows(t, capturedRowsQuery), dbscan.WithCapture(tc.column, tc.dst))
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
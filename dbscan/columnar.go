@@ -0,0 +1,147 @@
+package dbscan
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// isColumnarDestination reports whether ScanAll fills the destination struct column by column:
+// every field that a column is mapped to is a slice that receives the column values of all rows.
+func (api *API) isColumnarDestination(dst interface{}) bool {
+	dstType := reflect.TypeOf(dst)
+	if dstType == nil || dstType.Kind() != reflect.Ptr {
+		return false
+	}
+	structType := dstType.Elem()
+	if !api.isStructValue(structType) || isPositionalStruct(structType) {
+		return false
+	}
+	mapping, err := api.getStructMapping(structType)
+	if err != nil || len(mapping.columns) == 0 || mapping.remain != nil || len(mapping.wildcards) > 0 {
+		return false
+	}
+	hasSlices := false
+	for _, field := range mapping.columns {
+		fieldType := structType.FieldByIndex(field.Index).Type
+		switch {
+		case field.RowUnmarshaler:
+			return false
+		case fieldType.Kind() == reflect.Slice:
+			hasSlices = true
+		case !api.isNestedStruct(fieldType):
+			return false
+		}
+	}
+	return hasSlices
+}
+
+// isNestedStruct reports whether fields of the type are mapped to columns of their own.
+func (api *API) isNestedStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return api.isStructValue(t) && !isNullType(t)
+}
+
+// resetColumnarDestination makes sure all slice fields of the columnar destination are empty.
+func (api *API) resetColumnarDestination(dst interface{}) error {
+	structValue, err := parseDestination(dst)
+	if err != nil {
+		return fmt.Errorf("scany: parsing destination: %w", err)
+	}
+	mapping, err := api.getStructMapping(structValue.Type())
+	if err != nil {
+		return fmt.Errorf("scany: map columns to fields of %v: %w", structValue.Type(), err)
+	}
+	for _, field := range mapping.columns {
+		initializeNested(structValue, field.Index)
+		fieldVal := structValue.FieldByIndex(field.Index)
+		if fieldVal.Kind() == reflect.Slice {
+			fieldVal.Set(fieldVal.Slice(0, 0))
+		}
+	}
+	return nil
+}
+
+func startColumnarScanner(rs *RowScanner, dstValue reflect.Value) error {
+	var err error
+	rs.columns, err = rs.rows.Columns()
+	if err != nil {
+		return fmt.Errorf("scany: get rows columns: %w", err)
+	}
+	if err := rs.ensureDistinctColumns(nil /* positions */); err != nil {
+		return fmt.Errorf("duplicate columns: %w", err)
+	}
+	structType := dstValue.Type()
+	mapping, err := rs.api.getStructMapping(structType)
+	if err != nil {
+		return fmt.Errorf("scany: map columns to fields of %v: %w", structType, err)
+	}
+	rs.columnToFieldIndex = mapping.columns
+	rs.columnFields, err = rs.getColumnFields(mapping)
+	if err != nil {
+		return err
+	}
+	for i, column := range rs.columns {
+		field := rs.columnFields[i]
+		if field == nil && !rs.api.allowUnknownColumns {
+			return fmt.Errorf("scany: column: '%s': no corresponding field found, or it's unexported in %v",
+				column, structType)
+		}
+		if field != nil && structType.FieldByIndex(field.Index).Type.Kind() != reflect.Slice {
+			return fmt.Errorf("scany: column: '%s': field %s of columnar struct must be a slice, got: %v",
+				column, fieldPath(structType, field.Index), structType.FieldByIndex(field.Index).Type)
+		}
+	}
+	present, err := rs.getPresentFields()
+	if err != nil {
+		return err
+	}
+	if err := rs.ensureRequiredColumns(present); err != nil {
+		return err
+	}
+	rs.scanFn = rs.scanColumnar
+	return nil
+}
+
+// scanColumnar appends values of the current row to the slice fields of the columnar destination.
+// Fields grow in lockstep: values are appended only after the whole row is scanned successfully.
+func (rs *RowScanner) scanColumnar(structValue reflect.Value) error {
+	scans := make([]interface{}, len(rs.columns))
+	elems := make([]reflect.Value, len(rs.columns))
+	var finishers []finishScanFunc
+	for i, column := range rs.columns {
+		field := rs.columnFields[i]
+		if field == nil {
+			scans[i] = &Discard{}
+			continue
+		}
+		elemType := structValue.Type().FieldByIndex(field.Index).Type.Elem()
+		elems[i] = reflect.New(elemType).Elem()
+		var finish finishScanFunc
+		scans[i], finish = rs.api.fieldScanTarget(elems[i], column, field)
+		if finish != nil {
+			finishers = append(finishers, finish)
+		}
+	}
+	if err := rs.rows.Scan(scans...); err != nil {
+		return fmt.Errorf("scany: scan row into columnar struct fields: %w", err)
+	}
+	for _, finish := range finishers {
+		if _, err := finish(); err != nil {
+			return fmt.Errorf("scany: scan row into columnar struct fields: %w", err)
+		}
+	}
+	for i, elem := range elems {
+		if !elem.IsValid() {
+			continue
+		}
+		if rs.api.normalizationEnabled() {
+			rs.api.normalizeValue(elem, isPaddedColumn(rs.paddedColumns, i))
+		}
+		initializeNested(structValue, rs.columnFields[i].Index)
+		fieldVal := structValue.FieldByIndex(rs.columnFields[i].Index)
+		fieldVal.Set(reflect.Append(fieldVal, elem))
+	}
+	return nil
+}
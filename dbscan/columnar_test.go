@@ -0,0 +1,86 @@
+package dbscan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/georgysavva/scany/v2/dbscan"
+)
+
+func TestScanAll_columnarStruct(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT *
+		FROM (
+			VALUES ('2020-01-01'::TIMESTAMPTZ, 1::INT8, 'foo val'), ('2020-01-02', NULL, NULL)
+		) AS t (day, count, foo)
+	`
+	type series struct {
+		Count []*int64
+		Foo   []dbscan.Null[string]
+	}
+	got := struct {
+		Day    []time.Time
+		Series *series `db:",inline"`
+	}{
+		Day: []time.Time{{}},
+	}
+	err := testAPI.ScanAll(&got, queryRows(t, query))
+	require.NoError(t, err)
+
+	require.Len(t, got.Day, 2)
+	assert.True(t, got.Day[1].Equal(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)))
+	require.Len(t, got.Series.Count, 2)
+	assert.Equal(t, int64(1), *got.Series.Count[0])
+	assert.Nil(t, got.Series.Count[1])
+	assert.Equal(t, []dbscan.Null[string]{{V: "foo val", Valid: true}, {}}, got.Series.Foo)
+}
+
+func TestScanAll_columnarStruct_returnsErr(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name        string
+		query       string
+		dst         interface{}
+		expectedErr string
+	}{
+		{
+			name: "NULL into non nullable element",
+			query: `
+				SELECT *
+				FROM (
+					VALUES ('foo val', 'bar val'), ('foo val 2', NULL)
+				) AS t (foo, bar)
+			`,
+			dst: &struct {
+				Foo []string
+				Bar []string
+			}{},
+			expectedErr: "scanning: doing scan: scanFn: scany: scan row into columnar struct fields: " +
+				"can't scan into dest[1]: cannot scan NULL into *string",
+		},
+		{
+			name:  "column of non slice field",
+			query: `SELECT 'foo val' AS foo, 'bar val' AS nested`,
+			dst: &struct {
+				Foo    []string
+				Nested struct {
+					Bar []string
+				}
+			}{},
+			expectedErr: "scanning: doing scan: starting: scany: column: 'nested': " +
+				"field Nested of columnar struct must be a slice, got: struct { Bar []string }",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := testAPI.ScanAll(tc.dst, queryRows(t, tc.query))
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
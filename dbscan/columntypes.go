@@ -0,0 +1,210 @@
+package dbscan
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ColumnType describes a column of rows, see ColumnTypesRows.
+type ColumnType struct {
+	// Name is the column name.
+	Name string
+	// ScanType is the Go type that suits the column values, nil if it's unknown.
+	// For nullable columns it's the type of the value, not a Null wrapper.
+	ScanType reflect.Type
+	// DatabaseTypeName is the database type of the column, like "VARCHAR" or "INT8", empty if it's unknown.
+	DatabaseTypeName string
+	// Nullable reports whether the column can be NULL, it's meaningful only if NullableKnown is true.
+	// Many drivers don't report it, including pgx and PostgreSQL drivers for database/sql.
+	Nullable      bool
+	NullableKnown bool
+}
+
+// ColumnTypesRows is an optional interface that Rows can implement to describe types of their columns.
+// It's used to check that columns are compatible with struct fields before scanning, see WithColumnTypeCheck.
+type ColumnTypesRows interface {
+	Rows
+	ColumnTypes() ([]ColumnType, error)
+}
+
+// WithColumnTypeCheck makes dbscan check column types against the struct fields they are scanned into,
+// before scanning the first row. If Rows implement ColumnTypesRows interface,
+// dbscan returns IncompatibleColumnsError with all incompatible columns at once,
+// instead of a database library error on the first row that can't be scanned.
+//
+// A column is incompatible if it's nullable, but the field can't hold NULL,
+// or if the kinds of the column and the field values don't match, for example a textual column and a numeric field.
+// Fields of types that decode values by themselves, like sql.Scanner implementations or enums, aren't checked.
+//
+// Note that the nullability check works only if the driver reports whether columns are nullable,
+// see ColumnType.NullableKnown. Neither pgx nor PostgreSQL drivers for database/sql do,
+// so with PostgreSQL only kinds of values are checked and NULL values are still reported on the first such row.
+//
+// Only struct destinations are checked. Maps, Record, []interface{}, primitive types, columnar structs
+// and pivot scanning aren't checked, as well as remain and wildcard fields.
+func WithColumnTypeCheck(enabled bool) APIOption {
+	return func(api *API) {
+		api.columnTypeCheck = enabled
+	}
+}
+
+// IncompatibleColumn describes a column that can't be scanned into its struct field.
+type IncompatibleColumn struct {
+	Column           string
+	DatabaseTypeName string
+	// Field is the field path in the destination struct, nested fields are separated by ".".
+	Field     string
+	FieldType reflect.Type
+	Reason    string
+}
+
+// IncompatibleColumnsError is returned if columns aren't compatible with the struct fields, see WithColumnTypeCheck.
+type IncompatibleColumnsError struct {
+	Columns []IncompatibleColumn
+}
+
+func (e *IncompatibleColumnsError) Error() string {
+	reports := make([]string, len(e.Columns))
+	for i, c := range e.Columns {
+		column := fmt.Sprintf("'%s'", c.Column)
+		if c.DatabaseTypeName != "" {
+			column += " (" + c.DatabaseTypeName + ")"
+		}
+		reports[i] = fmt.Sprintf("column %s into field %s of type %v: %s", column, c.Field, c.FieldType, c.Reason)
+	}
+	return "scany: incompatible column types: " + strings.Join(reports, "; ")
+}
+
+// valueKind is a coarse kind of column and field values that is enough to tell whether they are compatible.
+type valueKind int
+
+const (
+	unknownValueKind valueKind = iota
+	numberValueKind
+	stringValueKind
+	boolValueKind
+	timeValueKind
+	bytesValueKind
+)
+
+func kindOfValue(t reflect.Type) valueKind {
+	switch {
+	case t == timeType:
+		return timeValueKind
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return bytesValueKind
+	case isNumberKind(t.Kind()):
+		return numberValueKind
+	case t.Kind() == reflect.String:
+		return stringValueKind
+	case t.Kind() == reflect.Bool:
+		return boolValueKind
+	default:
+		return unknownValueKind
+	}
+}
+
+// compatibleValueKinds reports whether a column value can be scanned into the field.
+// Strings and bytes fields accept any value, bytes columns may contain the textual form of any value.
+func compatibleValueKinds(column, field valueKind) bool {
+	switch {
+	case column == field, column == unknownValueKind, field == unknownValueKind:
+		return true
+	case field == stringValueKind, field == bytesValueKind, column == bytesValueKind:
+		return true
+	case field == boolValueKind:
+		return column == numberValueKind
+	default:
+		return false
+	}
+}
+
+// checkColumnTypes checks that columns are compatible with the struct fields they are scanned into.
+func (rs *RowScanner) checkColumnTypes(structType reflect.Type) error {
+	typedRows, ok := rs.rows.(ColumnTypesRows)
+	if !rs.api.columnTypeCheck || !ok {
+		return nil
+	}
+	columnTypes, err := typedRows.ColumnTypes()
+	if err != nil {
+		return fmt.Errorf("scany: get rows column types: %w", err)
+	}
+	if len(columnTypes) != len(rs.columns) {
+		return fmt.Errorf("scany: got %d column types, but rows contain %d columns", len(columnTypes), len(rs.columns))
+	}
+	var incompatible []IncompatibleColumn
+	for i, field := range rs.columnFields {
+		if field == nil || field.RowUnmarshaler || field.JSON {
+			continue
+		}
+		fieldType := structType.FieldByIndex(field.Index).Type
+		if reason := rs.api.incompatibilityReason(columnTypes[i], field, fieldType); reason != "" {
+			incompatible = append(incompatible, IncompatibleColumn{
+				Column:           rs.columns[i],
+				DatabaseTypeName: columnTypes[i].DatabaseTypeName,
+				Field:            fieldPath(structType, field.Index),
+				FieldType:        fieldType,
+				Reason:           reason,
+			})
+		}
+	}
+	if len(incompatible) > 0 {
+		return &IncompatibleColumnsError{Columns: incompatible}
+	}
+	return nil
+}
+
+// incompatibilityReason returns why the column can't be scanned into the field, it's empty if it can.
+func (api *API) incompatibilityReason(ct ColumnType, field *fieldMeta, fieldType reflect.Type) string {
+	canHoldNull := field.NullZero || field.Default.IsValid() || len(field.NullIndexes) > 0
+	switch fieldType.Kind() { //nolint: exhaustive
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		canHoldNull = true
+	}
+	if api.decodesItself(fieldType) {
+		return ""
+	}
+	if ct.NullableKnown && ct.Nullable && !canHoldNull {
+		return "column is nullable, but the field can't hold NULL"
+	}
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	if ct.ScanType == nil || api.decodesItself(fieldType) {
+		return ""
+	}
+	if !compatibleValueKinds(kindOfValue(ct.ScanType), kindOfValue(fieldType)) {
+		return fmt.Sprintf("%v value can't be scanned into the field", ct.ScanType)
+	}
+	return ""
+}
+
+// decodesItself reports whether values of the type are decoded by custom code that dbscan can't reason about.
+// Null is one of them, it holds NULL and converts values by itself.
+func (api *API) decodesItself(t reflect.Type) bool {
+	if t == timeType {
+		return false
+	}
+	ptrType := reflect.PtrTo(t)
+	return api.isScannableType(t) || ptrType.Implements(scannerType) || ptrType.Implements(textUnmarshalerType) ||
+		api.getEnumParser(t) != nil
+}
+
+// fieldPath returns the names of the fields by the index sequence separated by ".".
+// The value field of Null is left out, so the path is the same as the one used in Go code and Columns.
+func fieldPath(structType reflect.Type, index []int) string {
+	names := make([]string, 0, len(index))
+	t := structType
+	for _, fieldIndex := range index {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		field := t.Field(fieldIndex)
+		if !isNullType(t) {
+			names = append(names, field.Name)
+		}
+		t = field.Type
+	}
+	return strings.Join(names, ".")
+}
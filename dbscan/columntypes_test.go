@@ -0,0 +1,86 @@
+package dbscan_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/georgysavva/scany/v2/dbscan"
+)
+
+func TestRowScanner_Scan_withColumnTypeCheck(t *testing.T) {
+	t.Parallel()
+	type Nested struct {
+		Baz int
+	}
+	type dst struct {
+		Foo    int
+		Bar    string
+		Nested Nested
+		Qux    dbscan.Null[Nested]
+	}
+	api, err := getAPI(dbscan.WithColumnTypeCheck(true))
+	require.NoError(t, err)
+	rows := queryRows(t, `
+		SELECT 'foo val'::TEXT AS foo, 1::INT8 AS bar, 'baz val'::TEXT AS "nested.baz", 'baz val'::TEXT AS "qux.baz"
+	`)
+	defer rows.Close() //nolint: errcheck
+	rows.Next()
+
+	err = api.NewRowScanner(rows).Scan(&dst{})
+
+	expected := &dbscan.IncompatibleColumnsError{Columns: []dbscan.IncompatibleColumn{
+		{
+			Column:           "foo",
+			DatabaseTypeName: "TEXT",
+			Field:            "Foo",
+			FieldType:        reflect.TypeOf(0),
+			Reason:           "string value can't be scanned into the field",
+		},
+		{
+			Column:           "nested.baz",
+			DatabaseTypeName: "TEXT",
+			Field:            "Nested.Baz",
+			FieldType:        reflect.TypeOf(0),
+			Reason:           "string value can't be scanned into the field",
+		},
+		{
+			Column:           "qux.baz",
+			DatabaseTypeName: "TEXT",
+			Field:            "Qux.Baz",
+			FieldType:        reflect.TypeOf(0),
+			Reason:           "string value can't be scanned into the field",
+		},
+	}}
+	var got *dbscan.IncompatibleColumnsError
+	require.True(t, errors.As(err, &got))
+	assert.Equal(t, expected, got)
+	assert.EqualError(t, err, "doing scan: starting: scany: incompatible column types: "+
+		"column 'foo' (TEXT) into field Foo of type int: string value can't be scanned into the field; "+
+		"column 'nested.baz' (TEXT) into field Nested.Baz of type int: string value can't be scanned into the field; "+
+		"column 'qux.baz' (TEXT) into field Qux.Baz of type int: string value can't be scanned into the field")
+}
+
+func TestRowScanner_Scan_withColumnTypeCheck_compatibleColumns(t *testing.T) {
+	t.Parallel()
+	type dst struct {
+		Foo string
+		Bar float64
+		Baz *bool
+	}
+	api, err := getAPI(dbscan.WithColumnTypeCheck(true))
+	require.NoError(t, err)
+	rows := queryRows(t, `SELECT 'foo val'::TEXT AS foo, 1::INT8 AS bar, NULL::BOOL AS baz`)
+	defer rows.Close() //nolint: errcheck
+	rows.Next()
+
+	got := &dst{}
+	err = api.NewRowScanner(rows).Scan(got)
+	require.NoError(t, err)
+	requireNoRowsErrorsAndClose(t, rows)
+
+	assert.Equal(t, &dst{Foo: "foo val", Bar: 1}, got)
+}
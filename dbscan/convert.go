@@ -0,0 +1,281 @@
+package dbscan
+
+import (
+	"database/sql"
+	"encoding"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	scannerType         = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+)
+
+// timeLayouts is the list of layouts that dbscan tries in order when it needs to parse time from a string.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// assignValue converts a value returned by the database library into the type of dst and stores it.
+// It supports a subset of conversions that database/sql does for Rows.Scan:
+// nil resets dst to the zero value, sql.Scanner and encoding.TextUnmarshaler destinations are delegated to,
+// numeric and boolean values are converted between Go kinds,
+// and strings or bytes are parsed according to the destination type.
+func assignValue(dst reflect.Value, src interface{}) error {
+	if src == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	if dst.CanAddr() && dst.Addr().Type().Implements(scannerType) {
+		scanner := dst.Addr().Interface().(sql.Scanner)
+		if err := scanner.Scan(src); err != nil {
+			return fmt.Errorf("scany: scan %T into %v: %w", src, dst.Type(), err)
+		}
+		return nil
+	}
+	if dst.Kind() == reflect.Ptr {
+		elem := reflect.New(dst.Type().Elem())
+		if err := assignValue(elem.Elem(), src); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	}
+
+	srcValue := reflect.ValueOf(src)
+	switch s := src.(type) {
+	case []byte:
+		b := make([]byte, len(s))
+		copy(b, s)
+		if dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8 {
+			dst.SetBytes(b)
+			return nil
+		}
+		if dst.Kind() == reflect.Interface && reflect.TypeOf(b).Implements(dst.Type()) {
+			dst.Set(reflect.ValueOf(b))
+			return nil
+		}
+		return assignString(dst, string(s))
+	case string:
+		return assignString(dst, s)
+	}
+	if srcValue.Type().AssignableTo(dst.Type()) {
+		dst.Set(srcValue)
+		return nil
+	}
+	if dst.Kind() == reflect.Interface && srcValue.Type().Implements(dst.Type()) {
+		dst.Set(srcValue)
+		return nil
+	}
+	if isNumberKind(srcValue.Kind()) && isNumberKind(dst.Kind()) {
+		return assignNumber(dst, srcValue)
+	}
+	if srcValue.Kind() == reflect.Bool && dst.Kind() == reflect.Bool {
+		dst.SetBool(srcValue.Bool())
+		return nil
+	}
+	if dst.Kind() == reflect.String {
+		switch srcValue.Kind() { //nolint: exhaustive
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			dst.SetString(strconv.FormatInt(srcValue.Int(), 10))
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			dst.SetString(strconv.FormatUint(srcValue.Uint(), 10))
+			return nil
+		case reflect.Float32, reflect.Float64:
+			dst.SetString(strconv.FormatFloat(srcValue.Float(), 'g', -1, srcValue.Type().Bits()))
+			return nil
+		case reflect.Bool:
+			dst.SetString(strconv.FormatBool(srcValue.Bool()))
+			return nil
+		}
+	}
+	if srcValue.Type().ConvertibleTo(dst.Type()) && srcValue.Kind() == dst.Kind() {
+		dst.Set(srcValue.Convert(dst.Type()))
+		return nil
+	}
+	return fmt.Errorf("scany: unsupported conversion from %T into %v", src, dst.Type())
+}
+
+// assignString parses the string according to the type of dst and stores the result.
+func assignString(dst reflect.Value, s string) error {
+	if dst.Type() == timeType {
+		t, err := parseTime(s)
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if dst.CanAddr() && dst.Addr().Type().Implements(textUnmarshalerType) {
+		unmarshaler := dst.Addr().Interface().(encoding.TextUnmarshaler)
+		if err := unmarshaler.UnmarshalText([]byte(s)); err != nil {
+			return fmt.Errorf("scany: parse %q into %v: %w", s, dst.Type(), err)
+		}
+		return nil
+	}
+	if dst.Kind() == reflect.Ptr {
+		elem := reflect.New(dst.Type().Elem())
+		if err := assignString(elem.Elem(), s); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	}
+	if dst.Type() == durationType {
+		if d, err := time.ParseDuration(s); err == nil {
+			dst.SetInt(int64(d))
+			return nil
+		}
+	}
+
+	var err error
+	switch dst.Kind() {
+	case reflect.String:
+		dst.SetString(s)
+	case reflect.Slice:
+		if dst.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("scany: unsupported conversion from string into %v", dst.Type())
+		}
+		dst.SetBytes([]byte(s))
+	case reflect.Bool:
+		var v bool
+		v, err = strconv.ParseBool(s)
+		dst.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var v int64
+		v, err = strconv.ParseInt(s, 10, dst.Type().Bits())
+		dst.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var v uint64
+		v, err = strconv.ParseUint(s, 10, dst.Type().Bits())
+		dst.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		var v float64
+		v, err = strconv.ParseFloat(s, dst.Type().Bits())
+		dst.SetFloat(v)
+	case reflect.Interface:
+		if !reflect.TypeOf(s).Implements(dst.Type()) {
+			return fmt.Errorf("scany: unsupported conversion from string into %v", dst.Type())
+		}
+		dst.Set(reflect.ValueOf(s))
+	default:
+		return fmt.Errorf("scany: unsupported conversion from string into %v", dst.Type())
+	}
+	if err != nil {
+		return fmt.Errorf("scany: parse %q into %v: %w", s, dst.Type(), err)
+	}
+	return nil
+}
+
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("scany: parse %q into time.Time: unknown time format", s)
+}
+
+// assignNumber converts the number into the numeric kind of dst.
+// It returns an error instead of wrapping around or truncating the value:
+// negative values can't be stored into unsigned kinds, fractional values into integer kinds,
+// and every value must fit the size of dst.
+func assignNumber(dst, src reflect.Value) error {
+	outOfRange := fmt.Errorf("scany: converting %v to %v: value out of range", src.Interface(), dst.Type())
+	switch dst.Kind() { //nolint: exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, ok := numberToInt(src)
+		if !ok || dst.OverflowInt(v) {
+			return outOfRange
+		}
+		dst.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, ok := numberToUint(src)
+		if !ok || dst.OverflowUint(v) {
+			return outOfRange
+		}
+		dst.SetUint(v)
+	default:
+		converted := src.Convert(dst.Type())
+		if isFloatKind(src.Kind()) && dst.OverflowFloat(src.Float()) {
+			return outOfRange
+		}
+		// Integers must be represented exactly, large ones don't fit into the float mantissa.
+		if !isFloatKind(src.Kind()) && converted.Convert(src.Type()).Interface() != src.Interface() {
+			return outOfRange
+		}
+		dst.Set(converted)
+	}
+	return nil
+}
+
+// numberToInt returns the number as int64, it's false if the number isn't an integer in the int64 range.
+func numberToInt(src reflect.Value) (int64, bool) {
+	switch {
+	case isFloatKind(src.Kind()):
+		f := src.Float()
+		if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, false
+		}
+		return int64(f), true
+	case isUintKind(src.Kind()):
+		u := src.Uint()
+		return int64(u), u <= math.MaxInt64
+	default:
+		return src.Int(), true
+	}
+}
+
+// numberToUint returns the number as uint64, it's false if the number isn't a non negative integer
+// in the uint64 range.
+func numberToUint(src reflect.Value) (uint64, bool) {
+	switch {
+	case isFloatKind(src.Kind()):
+		f := src.Float()
+		if f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+			return 0, false
+		}
+		return uint64(f), true
+	case isUintKind(src.Kind()):
+		return src.Uint(), true
+	default:
+		i := src.Int()
+		return uint64(i), i >= 0
+	}
+}
+
+func isFloatKind(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
+
+func isUintKind(kind reflect.Kind) bool {
+	switch kind { //nolint: exhaustive
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
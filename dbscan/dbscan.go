@@ -4,8 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
-	"regexp"
-	"strings"
+	"sync"
+	"time"
 )
 
 // Rows is an abstract database rows that dbscan can iterate over and get the data from.
@@ -18,42 +18,85 @@ type Rows interface {
 	Scan(dest ...interface{}) error
 }
 
+// Discard is the Rows.Scan destination for columns whose values dbscan doesn't need,
+// like unknown columns with WithAllowUnknownColumns option, it drops the value.
+// Rows implementations can skip decoding such columns, pgxscan does.
+type Discard struct{}
+
+// Scan implements the sql.Scanner interface.
+func (*Discard) Scan(interface{}) error { return nil }
+
 // ScanAll is a package-level helper function that uses the DefaultAPI object.
 // See API.ScanAll for details.
-func ScanAll(dst interface{}, rows Rows) error {
-	return DefaultAPI.ScanAll(dst, rows)
+func ScanAll(dst interface{}, rows Rows, opts ...ScanOption) error {
+	return DefaultAPI.ScanAll(dst, rows, opts...)
 }
 
 // ScanOne is a package-level helper function that uses the DefaultAPI object.
 // See API.ScanOne for details.
-func ScanOne(dst interface{}, rows Rows) error {
-	return DefaultAPI.ScanOne(dst, rows)
+func ScanOne(dst interface{}, rows Rows, opts ...ScanOption) error {
+	return DefaultAPI.ScanOne(dst, rows, opts...)
+}
+
+// ScanOneValues is a package-level helper function that uses the DefaultAPI object.
+// See API.ScanOneValues for details.
+func ScanOneValues(rows Rows, dst ...interface{}) error {
+	return DefaultAPI.ScanOneValues(rows, dst...)
+}
+
+// FieldMapper maps a struct field without the struct tag to the database column name.
+// Unlike NameMapperFunc it receives the whole field, including its type and other tags,
+// the type of the struct that contains the field and the column prefix of that struct,
+// that is empty for fields of the destination struct itself.
+//
+// For a nested struct field the returned name is used as the prefix for columns of the nested struct.
+// For an embedded struct field it works the same way, an empty name means no prefix.
+// If the mapper returns "-", the field is ignored.
+type FieldMapper interface {
+	MapField(field reflect.StructField, parent reflect.Type, columnPrefix string) string
+}
+
+// FieldMapperFunc is an adapter to allow the use of ordinary functions as FieldMapper.
+type FieldMapperFunc func(field reflect.StructField, parent reflect.Type, columnPrefix string) string
+
+// MapField calls f(field, parent, columnPrefix).
+func (f FieldMapperFunc) MapField(field reflect.StructField, parent reflect.Type, columnPrefix string) string {
+	return f(field, parent, columnPrefix)
 }
 
 // NameMapperFunc is a function type that maps a struct field name to the database column name.
 type NameMapperFunc func(string) string
 
-var (
-	matchFirstCapRe = regexp.MustCompile("(.)([A-Z][a-z]+)")
-	matchAllCapRe   = regexp.MustCompile("([a-z0-9])([A-Z])")
-)
-
-// SnakeCaseMapper is a NameMapperFunc that maps struct field to snake case.
-func SnakeCaseMapper(str string) string {
-	snake := matchFirstCapRe.ReplaceAllString(str, "${1}_${2}")
-	snake = matchAllCapRe.ReplaceAllString(snake, "${1}_${2}")
-	return strings.ToLower(snake)
+// MapField implements FieldMapper by mapping the field name.
+// Embedded struct fields are mapped to the empty name, so their columns don't get any prefix.
+func (f NameMapperFunc) MapField(field reflect.StructField, _ reflect.Type, _ string) string {
+	if field.Anonymous {
+		return ""
+	}
+	return f(field.Name)
 }
 
 // API is the core type in dbscan. It implements all the logic and exposes functionality available in the package.
 // With API type users can create a custom API instance and override default settings hence configure dbscan.
 type API struct {
-	structTagKey          string
+	structTagKeys         []string
 	columnSeparator       string
-	fieldMapperFn         NameMapperFunc
+	fieldMapper           FieldMapper
 	scannableTypesOption  []interface{}
 	scannableTypesReflect []reflect.Type
 	allowUnknownColumns   bool
+	enumOptions           []*enumOption
+	enums                 map[reflect.Type]map[string]reflect.Value
+	discriminatorOptions  []*discriminatorOption
+	discriminators        map[reflect.Type]*discriminatorOption
+	discriminatorPlansMu  sync.RWMutex
+	discriminatorPlans    map[discriminatorPlanKey]*discriminatorPlan
+	timeLocation          *time.Location
+	trimCharPadding       bool
+	emptyStringAsNil      bool
+	columnTypeCheck       bool
+	mappingsMu            sync.RWMutex
+	mappings              map[reflect.Type]map[string]string
 }
 
 // APIOption is a function type that changes API configuration.
@@ -62,9 +105,9 @@ type APIOption func(api *API)
 // NewAPI creates a new API object with provided list of options.
 func NewAPI(opts ...APIOption) (*API, error) {
 	api := &API{
-		structTagKey:        "db",
+		structTagKeys:       []string{"db"},
 		columnSeparator:     ".",
-		fieldMapperFn:       SnakeCaseMapper,
+		fieldMapper:         NameMapperFunc(SnakeCaseMapper),
 		allowUnknownColumns: false,
 	}
 	for _, o := range opts {
@@ -86,14 +129,31 @@ func NewAPI(opts ...APIOption) (*API, error) {
 		}
 		api.scannableTypesReflect = append(api.scannableTypesReflect, st)
 	}
+	if len(api.structTagKeys) == 0 {
+		return nil, fmt.Errorf("scany: at least one struct tag key is required")
+	}
+	if err := api.registerEnums(); err != nil {
+		return nil, err
+	}
+	if err := api.registerDiscriminators(); err != nil {
+		return nil, err
+	}
 	return api, nil
 }
 
 // WithStructTagKey allows to use a custom struct tag key.
 // The default tag key is `db`.
 func WithStructTagKey(tagKey string) APIOption {
+	return WithStructTagKeys(tagKey)
+}
+
+// WithStructTagKeys allows to use several struct tag keys.
+// For each field dbscan consults the keys in the provided order and uses the first tag present,
+// for example, WithStructTagKeys("db", "sql", "json") falls back to `json` tag
+// if a field doesn't have `db` and `sql` tags.
+func WithStructTagKeys(tagKeys ...string) APIOption {
 	return func(api *API) {
-		api.structTagKey = tagKey
+		api.structTagKeys = tagKeys
 	}
 }
 
@@ -107,9 +167,16 @@ func WithColumnSeparator(separator string) APIOption {
 
 // WithFieldNameMapper allows to use a custom function to map field name to column names.
 // The default function is SnakeCaseMapper.
+// It's a shortcut for WithFieldMapper(mapperFn).
 func WithFieldNameMapper(mapperFn NameMapperFunc) APIOption {
+	return WithFieldMapper(mapperFn)
+}
+
+// WithFieldMapper allows to use a custom FieldMapper to map struct fields without the struct tag to column names.
+// It overrides WithFieldNameMapper and vice versa.
+func WithFieldMapper(mapper FieldMapper) APIOption {
 	return func(api *API) {
-		api.fieldMapperFn = mapperFn
+		api.fieldMapper = mapper
 	}
 }
 
@@ -161,8 +228,18 @@ func WithAllowUnknownColumns(allowUnknownColumns bool) APIOption {
 //
 // Before starting, ScanAll resets the destination slice,
 // so if it's not empty it will overwrite all existing elements.
-func (api *API) ScanAll(dst interface{}, rows Rows) error {
-	return api.processRows(dst, rows, true /* multipleRows. */)
+//
+// The destination can also be a map keyed by a column value, like map[int64]User or map[int64][]Order,
+// see "Scanning into keyed maps" section in the package doc. Scan options configure how keys are taken.
+//
+// A pointer to a struct with slice fields, like struct{ Day []time.Time; Count []int64 },
+// is a columnar destination: every field receives the values of its column from all rows,
+// see "Scanning into columnar structs" section in the package doc.
+//
+// Columns that aren't part of the element, like a total count of a paginated query,
+// can be scanned into separate variables with WithCapture scan option.
+func (api *API) ScanAll(dst interface{}, rows Rows, opts ...ScanOption) error {
+	return api.processRows(dst, rows, true /* multipleRows. */, opts)
 }
 
 // ScanOne iterates all rows to the end and makes sure that there was exactly one row
@@ -170,8 +247,18 @@ func (api *API) ScanAll(dst interface{}, rows Rows) error {
 // After iterating ScanOne closes the rows,
 // and propagates any errors that could pop up.
 // It scans data from that single row into the destination.
-func (api *API) ScanOne(dst interface{}, rows Rows) error {
-	return api.processRows(dst, rows, false /* multipleRows. */)
+// Scan options work the same way as for ScanAll, except the ones that configure map keys,
+// since a map destination of ScanOne receives columns of the row.
+func (api *API) ScanOne(dst interface{}, rows Rows, opts ...ScanOption) error {
+	return api.processRows(dst, rows, false /* multipleRows. */, opts)
+}
+
+// ScanOneValues is the same as ScanOne, but it scans columns of the single row into multiple destinations by position.
+// Every destination must be a non nil pointer, there must be exactly one destination per column.
+// Column names don't matter and may repeat.
+func (api *API) ScanOneValues(rows Rows, dst ...interface{}) error {
+	values := rowValues(dst)
+	return api.processRows(&values, rows, false /* multipleRows. */, nil /* opts */)
 }
 
 // NotFound returns true if err is a not found error.
@@ -189,28 +276,25 @@ type sliceDestinationMeta struct {
 	elementByPtr    bool
 }
 
-func (api *API) processRows(dst interface{}, rows Rows, multipleRows bool) error {
+func (api *API) processRows(dst interface{}, rows Rows, multipleRows bool, opts []ScanOption) error {
 	defer rows.Close() //nolint: errcheck
-	var sliceMeta *sliceDestinationMeta
-	if multipleRows {
-		var err error
-		sliceMeta, err = api.parseSliceDestination(dst)
-		if err != nil {
-			return fmt.Errorf("parsing slice destination: %w", err)
-		}
-		// Make sure slice is empty.
-		sliceMeta.val.Set(sliceMeta.val.Slice(0, 0))
+	cfg := newScanConfig(opts)
+	captured, elementRows, err := newCapturedColumns(rows, cfg.captures)
+	if err != nil {
+		return err
+	}
+	scanElement, err := api.elementScanner(dst, elementRows, multipleRows, cfg)
+	if err != nil {
+		return err
 	}
-	rs := api.NewRowScanner(rows)
 	var rowsAffected int
 	for rows.Next() {
-		var err error
-		if multipleRows {
-			err = scanSliceElement(rs, sliceMeta)
-		} else {
-			err = rs.Scan(dst)
+		if captured != nil && rowsAffected == 0 {
+			if err := captured.scan(api, rows); err != nil {
+				return fmt.Errorf("scanning: %w", err)
+			}
 		}
-		if err != nil {
+		if err := scanElement(); err != nil {
 			return fmt.Errorf("scanning: %w", err)
 		}
 		rowsAffected++
@@ -235,6 +319,50 @@ func (api *API) processRows(dst interface{}, rows Rows, multipleRows bool) error
 	return nil
 }
 
+// elementScanner returns the function that scans the current row into the destination.
+func (api *API) elementScanner(dst interface{}, rows Rows, multipleRows bool, cfg *scanConfig) (func() error, error) {
+	rs := api.NewRowScanner(rows)
+	rs.sparseFields = cfg.sparseFields
+	switch {
+	case multipleRows && isMapDestination(dst):
+		mapMeta, err := api.parseMapDestination(dst, rows, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("parsing map destination: %w", err)
+		}
+		rs = api.NewRowScanner(mapMeta.valueRows)
+		rs.sparseFields = cfg.sparseFields
+		return func() error { return api.scanMapElement(rs, rows, mapMeta) }, nil
+	case multipleRows && api.isColumnarDestination(dst):
+		if err := api.resetColumnarDestination(dst); err != nil {
+			return nil, fmt.Errorf("parsing columnar destination: %w", err)
+		}
+		rs.start = startColumnarScanner
+	case multipleRows:
+		sliceMeta, err := api.parseSliceDestination(dst)
+		if err != nil {
+			return nil, fmt.Errorf("parsing slice destination: %w", err)
+		}
+		// Make sure slice is empty.
+		sliceMeta.val.Set(sliceMeta.val.Slice(0, 0))
+		if opt, ok := api.discriminators[sliceMeta.elementBaseType]; ok {
+			ps, err := api.newPolymorphicScanner(rows, opt)
+			if err != nil {
+				return nil, err
+			}
+			ps.sparseFields = cfg.sparseFields
+			return func() error { return scanPolymorphicSliceElement(ps, sliceMeta) }, nil
+		}
+		return func() error { return scanSliceElement(rs, sliceMeta) }, nil
+	case api.isDiscriminatedDestination(dst):
+		ps, err := api.newPolymorphicScanner(rows, api.discriminators[reflect.TypeOf(dst).Elem()])
+		if err != nil {
+			return nil, err
+		}
+		return func() error { return ps.scan(reflect.ValueOf(dst).Elem()) }, nil
+	}
+	return func() error { return rs.Scan(dst) }, nil
+}
+
 func (api *API) parseSliceDestination(dst interface{}) (*sliceDestinationMeta, error) {
 	dstValue, err := parseDestination(dst)
 	if err != nil {
@@ -249,28 +377,33 @@ func (api *API) parseSliceDestination(dst interface{}) (*sliceDestinationMeta, e
 		)
 	}
 
-	elementBaseType := dstType.Elem()
-	var elementByPtr bool
+	elementBaseType, elementByPtr := api.elementBaseType(dstType.Elem())
+	meta := &sliceDestinationMeta{
+		val:             dstValue,
+		elementBaseType: elementBaseType,
+		elementByPtr:    elementByPtr,
+	}
+	return meta, nil
+}
+
+// elementBaseType returns the type that rows are scanned into for elements of the slice or map destination
+// and reports whether elements are pointers to that type.
+func (api *API) elementBaseType(elementType reflect.Type) (reflect.Type, bool) {
 	// If it's a slice of pointers to structs,
 	// we handle it the same way as it would be slice of struct by value
 	// and dereference pointers to values,
 	// because eventually we work with fields.
 	// But if it's a slice of primitive type e.g. or []string or []*string,
 	// we must leave and pass elements as is to Rows.Scan().
-	if elementBaseType.Kind() == reflect.Ptr {
-		elementBaseTypeElem := elementBaseType.Elem()
-		if elementBaseTypeElem.Kind() == reflect.Struct && !api.isScannableType(elementBaseType) {
-			elementBaseType = elementBaseTypeElem
-			elementByPtr = true
+	// The same applies to slices of pointers to RowUnmarshaler types.
+	if elementType.Kind() == reflect.Ptr {
+		elementBaseTypeElem := elementType.Elem()
+		isStruct := elementBaseTypeElem.Kind() == reflect.Struct && !api.isScannableType(elementType)
+		if isStruct || implementsRowUnmarshaler(elementType) {
+			return elementBaseTypeElem, true
 		}
 	}
-
-	meta := &sliceDestinationMeta{
-		val:             dstValue,
-		elementBaseType: elementBaseType,
-		elementByPtr:    elementByPtr,
-	}
-	return meta, nil
+	return elementType, false
 }
 
 func scanSliceElement(rs *RowScanner, sliceMeta *sliceDestinationMeta) error {
@@ -289,6 +422,15 @@ func scanSliceElement(rs *RowScanner, sliceMeta *sliceDestinationMeta) error {
 	return nil
 }
 
+func scanPolymorphicSliceElement(ps *polymorphicScanner, sliceMeta *sliceDestinationMeta) error {
+	elemVal := reflect.New(sliceMeta.elementBaseType).Elem()
+	if err := ps.scan(elemVal); err != nil {
+		return fmt.Errorf("scanning: %w", err)
+	}
+	sliceMeta.val.Set(reflect.Append(sliceMeta.val, elemVal))
+	return nil
+}
+
 // ScanRow is a package-level helper function that uses the DefaultAPI object.
 // See API.ScanRow for details.
 func ScanRow(dst interface{}, rows Rows) error {
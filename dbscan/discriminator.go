@@ -0,0 +1,292 @@
+package dbscan
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// UnknownDiscriminatorError is returned when the discriminator column contains a value
+// that doesn't have a registered factory.
+type UnknownDiscriminatorError struct {
+	Column string
+	Type   reflect.Type
+	Value  string
+}
+
+// Error implements the error interface.
+func (e *UnknownDiscriminatorError) Error() string {
+	return fmt.Sprintf("scany: column: '%s': unknown discriminator value %q for %v", e.Column, e.Value, e.Type)
+}
+
+type discriminatorOption struct {
+	ifaceType reflect.Type
+	column    string
+	factories map[string]func() reflect.Value
+	typesOnce sync.Once
+	types     []reflect.Type
+}
+
+// WithDiscriminator registers an interface type, so dbscan can scan rows into values of that interface.
+// The value of the column selects the factory that creates the concrete value the row is scanned into,
+// for example:
+//
+//	type Event interface{ EventID() int64 }
+//
+//	dbscan.WithDiscriminator("kind", map[string]func() Event{
+//	    "click": func() Event { return &ClickEvent{} },
+//	    "view":  func() Event { return &ViewEvent{} },
+//	})
+//
+// Factories must return non nil pointers, every factory is called once more to find out its concrete type.
+// The discriminator column is passed to the concrete value only if it has a corresponding struct field.
+// Columns that only other concrete types have fields for aren't passed either,
+// so rows of UNION ALL over different tables can be scanned without WithAllowUnknownColumns option.
+// ScanAll scans into slices like []Event, ScanOne scans into *Event.
+// If there is no factory for the column value, the UnknownDiscriminatorError is returned.
+func WithDiscriminator[T any](column string, factories map[string]func() T) APIOption {
+	opt := &discriminatorOption{
+		ifaceType: reflect.TypeOf((*T)(nil)).Elem(),
+		column:    column,
+		factories: make(map[string]func() reflect.Value, len(factories)),
+	}
+	for value, factory := range factories {
+		factory := factory
+		opt.factories[value] = func() reflect.Value {
+			return reflect.ValueOf(factory())
+		}
+	}
+	return func(api *API) {
+		api.discriminatorOptions = append(api.discriminatorOptions, opt)
+	}
+}
+
+func (api *API) registerDiscriminators() error {
+	api.discriminators = make(map[reflect.Type]*discriminatorOption, len(api.discriminatorOptions))
+	for _, opt := range api.discriminatorOptions {
+		if opt.ifaceType.Kind() != reflect.Interface {
+			return fmt.Errorf("scany: discriminated type must be an interface, got %s: %v",
+				opt.ifaceType.Kind(), opt.ifaceType)
+		}
+		if _, exists := api.discriminators[opt.ifaceType]; exists {
+			return fmt.Errorf("scany: discriminated type %v is registered more than once", opt.ifaceType)
+		}
+		api.discriminators[opt.ifaceType] = opt
+	}
+	return nil
+}
+
+// isDiscriminatedDestination reports whether dst is a pointer to an interface registered via WithDiscriminator.
+func (api *API) isDiscriminatedDestination(dst interface{}) bool {
+	dstValue := reflect.ValueOf(dst)
+	if dstValue.Kind() != reflect.Ptr || dstValue.IsNil() {
+		return false
+	}
+	_, ok := api.discriminators[dstValue.Type().Elem()]
+	return ok
+}
+
+// polymorphicScanner scans rows into concrete values selected by the discriminator column.
+type polymorphicScanner struct {
+	api      *API
+	rows     Rows
+	option   *discriminatorOption
+	columns  []string
+	position int
+	// sparseFields is passed to RowScanner of every concrete type, see WithSparseFields.
+	sparseFields bool
+	// scanners contains a RowScanner for every concrete type met in the rows.
+	scanners map[reflect.Type]*RowScanner
+}
+
+func (api *API) newPolymorphicScanner(rows Rows, opt *discriminatorOption) (*polymorphicScanner, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("scany: get rows columns: %w", err)
+	}
+	position := columnPosition(columns, opt.column)
+	if position < 0 {
+		return nil, fmt.Errorf("scany: discriminator column '%s' isn't found in rows", opt.column)
+	}
+	ps := &polymorphicScanner{
+		api:      api,
+		rows:     rows,
+		option:   opt,
+		columns:  columns,
+		position: position,
+		scanners: make(map[reflect.Type]*RowScanner),
+	}
+	return ps, nil
+}
+
+// scan scans the current row into a new concrete value and stores it into dstValue of the interface type.
+func (ps *polymorphicScanner) scan(dstValue reflect.Value) error {
+	name, err := ps.scanDiscriminator()
+	if err != nil {
+		return err
+	}
+	factory, ok := ps.option.factories[name]
+	if !ok {
+		return &UnknownDiscriminatorError{Column: ps.option.column, Type: ps.option.ifaceType, Value: name}
+	}
+	value := factory()
+	if !isConcreteValue(value) {
+		var got interface{}
+		if value.IsValid() {
+			got = value.Interface()
+		}
+		return fmt.Errorf("scany: discriminator factory for %q must return a non nil pointer, got: %T", name, got)
+	}
+	rs, err := ps.scanner(value.Type().Elem())
+	if err != nil {
+		return err
+	}
+	if err := rs.doScan(value.Elem()); err != nil {
+		return fmt.Errorf("doing scan: %w", err)
+	}
+	dstValue.Set(value)
+	return nil
+}
+
+// scanDiscriminator reads the discriminator column of the current row, other columns are discarded.
+// The row is scanned once more into the concrete value, the same way as for nested RowUnmarshaler fields.
+func (ps *polymorphicScanner) scanDiscriminator() (string, error) {
+	var discriminator interface{}
+	scans := discardScanTargets(len(ps.columns))
+	scans[ps.position] = &discriminator
+	if err := ps.rows.Scan(scans...); err != nil {
+		return "", fmt.Errorf("scany: scan discriminator column '%s': %w", ps.option.column, err)
+	}
+	switch v := discriminator.(type) {
+	case nil:
+		return "", fmt.Errorf("scany: discriminator column '%s' is NULL", ps.option.column)
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+func isConcreteValue(value reflect.Value) bool {
+	return value.IsValid() && value.Kind() == reflect.Ptr && !value.IsNil()
+}
+
+func (ps *polymorphicScanner) scanner(t reflect.Type) (*RowScanner, error) {
+	if rs, ok := ps.scanners[t]; ok {
+		return rs, nil
+	}
+	plan, err := ps.api.getDiscriminatorPlan(ps.option, t)
+	if err != nil {
+		return nil, err
+	}
+	rows := ps.rows
+	if hidden := plan.hiddenColumns(ps.columns, ps.position); len(hidden) > 0 {
+		rows = newHiddenColumnsRows(rows, hidden)
+	}
+	rs := ps.api.NewRowScanner(rows)
+	rs.sparseFields = ps.sparseFields
+	ps.scanners[t] = rs
+	return rs, nil
+}
+
+// discriminatorPlan describes which columns a concrete type of the interface receives.
+type discriminatorPlan struct {
+	// columns contains the columns the type receives by name, allColumns is true if it receives every column.
+	columns    map[string]*fieldMeta
+	allColumns bool
+	// foreign contains the columns that other concrete types receive by name,
+	// allForeign is true if another type receives every column.
+	foreign    map[string]struct{}
+	allForeign bool
+}
+
+type discriminatorPlanKey struct {
+	option       *discriminatorOption
+	concreteType reflect.Type
+}
+
+// getDiscriminatorPlan returns the plan of the concrete type,
+// plans are cached by the API, so every concrete type is planned once.
+func (api *API) getDiscriminatorPlan(opt *discriminatorOption, t reflect.Type) (*discriminatorPlan, error) {
+	key := discriminatorPlanKey{option: opt, concreteType: t}
+	api.discriminatorPlansMu.RLock()
+	plan, ok := api.discriminatorPlans[key]
+	api.discriminatorPlansMu.RUnlock()
+	if ok {
+		return plan, nil
+	}
+	plan, err := api.newDiscriminatorPlan(opt, t)
+	if err != nil {
+		return nil, err
+	}
+	api.discriminatorPlansMu.Lock()
+	if api.discriminatorPlans == nil {
+		api.discriminatorPlans = make(map[discriminatorPlanKey]*discriminatorPlan)
+	}
+	api.discriminatorPlans[key] = plan
+	api.discriminatorPlansMu.Unlock()
+	return plan, nil
+}
+
+func (api *API) newDiscriminatorPlan(opt *discriminatorOption, t reflect.Type) (*discriminatorPlan, error) {
+	columns, all, err := api.receivedColumns(t)
+	if err != nil {
+		return nil, err
+	}
+	plan := &discriminatorPlan{columns: columns, allColumns: all, foreign: make(map[string]struct{})}
+	for _, other := range opt.concreteTypes() {
+		if other == t {
+			continue
+		}
+		columns, all, err := api.receivedColumns(other)
+		if err != nil {
+			return nil, err
+		}
+		plan.allForeign = plan.allForeign || all
+		for column := range columns {
+			plan.foreign[column] = struct{}{}
+		}
+	}
+	return plan, nil
+}
+
+// hiddenColumns returns positions of the columns that the concrete type doesn't receive:
+// the discriminator column if the type doesn't have a field for it
+// and columns that only other concrete types of the interface have fields for.
+func (plan *discriminatorPlan) hiddenColumns(columns []string, position int) []int {
+	if plan.allColumns {
+		return nil
+	}
+	var hidden []int
+	for i, column := range columns {
+		if _, ok := plan.columns[column]; ok {
+			continue
+		}
+		_, foreign := plan.foreign[column]
+		if i == position || foreign || plan.allForeign {
+			hidden = append(hidden, i)
+		}
+	}
+	return hidden
+}
+
+// concreteTypes returns the types of values created by the factories, every factory is called once.
+// Factories that don't return a non nil pointer are skipped, scan reports them when their value is met.
+func (opt *discriminatorOption) concreteTypes() []reflect.Type {
+	opt.typesOnce.Do(func() {
+		seen := make(map[reflect.Type]struct{}, len(opt.factories))
+		for _, factory := range opt.factories {
+			value := factory()
+			if !isConcreteValue(value) {
+				continue
+			}
+			if _, ok := seen[value.Type().Elem()]; !ok {
+				seen[value.Type().Elem()] = struct{}{}
+				opt.types = append(opt.types, value.Type().Elem())
+			}
+		}
+	})
+	return opt.types
+}
@@ -0,0 +1,222 @@
+package dbscan_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/georgysavva/scany/v2/dbscan"
+)
+
+type testEvent interface {
+	eventID() int64
+}
+
+type testClickEvent struct {
+	ID  int64
+	Foo string
+}
+
+func (e *testClickEvent) eventID() int64 { return e.ID }
+
+type testViewEvent struct {
+	ID   int64
+	Kind string
+	Bar  string `db:"foo"`
+}
+
+func (e *testViewEvent) eventID() int64 { return e.ID }
+
+type testPurchaseEvent struct {
+	ID     int64
+	Amount int64
+}
+
+func (e *testPurchaseEvent) eventID() int64 { return e.ID }
+
+var testEventFactories = map[string]func() testEvent{
+	"click": func() testEvent { return &testClickEvent{} },
+	"view":  func() testEvent { return &testViewEvent{} },
+}
+
+const eventRowsQuery = `
+	SELECT *
+	FROM (
+		VALUES (1::INT8, 'click', 'foo val'), (2, 'view', 'foo val 2'), (3, 'click', 'foo val 3')
+	) AS t (id, kind, foo)
+`
+
+func TestScanAll_discriminatedInterfaceSlice(t *testing.T) {
+	t.Parallel()
+	api, err := getAPI(dbscan.WithDiscriminator("kind", testEventFactories))
+	require.NoError(t, err)
+	expected := []testEvent{
+		&testClickEvent{ID: 1, Foo: "foo val"},
+		&testViewEvent{ID: 2, Kind: "view", Bar: "foo val 2"},
+		&testClickEvent{ID: 3, Foo: "foo val 3"},
+	}
+
+	var got []testEvent
+	err = api.ScanAll(&got, queryRows(t, eventRowsQuery))
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
+
+func TestScanAll_discriminatedInterfaceSlice_differentColumnSets(t *testing.T) {
+	t.Parallel()
+	api, err := getAPI(dbscan.WithDiscriminator("kind", map[string]func() testEvent{
+		"click":    func() testEvent { return &testClickEvent{} },
+		"purchase": func() testEvent { return &testPurchaseEvent{} },
+	}))
+	require.NoError(t, err)
+	query := `
+		SELECT 1::INT8 AS id, 'click' AS kind, 'foo val' AS foo, NULL::INT8 AS amount
+		UNION ALL
+		SELECT 2::INT8 AS id, 'purchase' AS kind, NULL AS foo, 10::INT8 AS amount
+	`
+	expected := []testEvent{
+		&testClickEvent{ID: 1, Foo: "foo val"},
+		&testPurchaseEvent{ID: 2, Amount: 10},
+	}
+
+	var got []testEvent
+	err = api.ScanAll(&got, queryRows(t, query))
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
+
+func TestScanOne_discriminatedInterface_scansValuesNatively(t *testing.T) {
+	t.Parallel()
+	api, err := getAPI(dbscan.WithDiscriminator("kind", testEventFactories))
+	require.NoError(t, err)
+	// UUID isn't scanned into interface{} as a string, but the driver scans it into a string field.
+	query := `
+		SELECT 1::INT8 AS id, 'click' AS kind, 'f47ac10b-58cc-4372-a567-0e02b2c3d479'::UUID AS foo
+	`
+
+	var got testEvent
+	err = api.ScanOne(&got, queryRows(t, query))
+	require.NoError(t, err)
+
+	assert.Equal(t, &testClickEvent{ID: 1, Foo: "f47ac10b-58cc-4372-a567-0e02b2c3d479"}, got)
+}
+
+func TestScanOne_discriminatedInterface(t *testing.T) {
+	t.Parallel()
+	api, err := getAPI(dbscan.WithDiscriminator("kind", testEventFactories))
+	require.NoError(t, err)
+	query := `
+		SELECT 2::INT8 AS id, 'view' AS kind, 'foo val' AS foo
+	`
+
+	var got testEvent
+	err = api.ScanOne(&got, queryRows(t, query))
+	require.NoError(t, err)
+
+	assert.Equal(t, &testViewEvent{ID: 2, Kind: "view", Bar: "foo val"}, got)
+}
+
+func TestScanAll_unknownDiscriminatorValue_returnsErr(t *testing.T) {
+	t.Parallel()
+	api, err := getAPI(dbscan.WithDiscriminator("kind", map[string]func() testEvent{
+		"click": func() testEvent { return &testClickEvent{} },
+	}))
+	require.NoError(t, err)
+
+	var got []testEvent
+	err = api.ScanAll(&got, queryRows(t, eventRowsQuery))
+
+	var discriminatorErr *dbscan.UnknownDiscriminatorError
+	require.True(t, errors.As(err, &discriminatorErr))
+	assert.Equal(t, "kind", discriminatorErr.Column)
+	assert.Equal(t, "view", discriminatorErr.Value)
+	assert.EqualError(t, err, "scanning: scanning: "+
+		"scany: column: 'kind': unknown discriminator value \"view\" for dbscan_test.testEvent")
+}
+
+func TestScanAll_discriminator_returnsErr(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name        string
+		factories   map[string]func() testEvent
+		query       string
+		expectedErr string
+	}{
+		{
+			name:      "absent column",
+			factories: testEventFactories,
+			query: `
+				SELECT 1::INT8 AS id, 'foo val' AS foo
+			`,
+			expectedErr: "scany: discriminator column 'kind' isn't found in rows",
+		},
+		{
+			name:      "NULL discriminator",
+			factories: testEventFactories,
+			query: `
+				SELECT 1::INT8 AS id, NULL::TEXT AS kind, 'foo val' AS foo
+			`,
+			expectedErr: "scanning: scanning: scany: discriminator column 'kind' is NULL",
+		},
+		{
+			name: "nil factory result",
+			factories: map[string]func() testEvent{
+				"click": func() testEvent { return (*testClickEvent)(nil) },
+			},
+			query: `
+				SELECT 1::INT8 AS id, 'click' AS kind, 'foo val' AS foo
+			`,
+			expectedErr: "scanning: scanning: scany: discriminator factory for \"click\" " +
+				"must return a non nil pointer, got: *dbscan_test.testClickEvent",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			api, err := getAPI(dbscan.WithDiscriminator("kind", tc.factories))
+			require.NoError(t, err)
+			var got []testEvent
+			err = api.ScanAll(&got, queryRows(t, tc.query))
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestNewAPI_WithDiscriminator_InvalidInput(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name        string
+		opts        []dbscan.APIOption
+		expectedErr string
+	}{
+		{
+			name: "non interface type",
+			opts: []dbscan.APIOption{
+				dbscan.WithDiscriminator("kind", map[string]func() *testClickEvent{}),
+			},
+			expectedErr: "scany: discriminated type must be an interface, got ptr: *dbscan_test.testClickEvent",
+		},
+		{
+			name: "duplicate registration",
+			opts: []dbscan.APIOption{
+				dbscan.WithDiscriminator("kind", testEventFactories),
+				dbscan.WithDiscriminator("type", testEventFactories),
+			},
+			expectedErr: "scany: discriminated type dbscan_test.testEvent is registered more than once",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			api, err := dbscan.NewAPI(tc.opts...)
+			assert.EqualError(t, err, tc.expectedErr)
+			assert.Nil(t, api)
+		})
+	}
+}
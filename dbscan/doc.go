@@ -24,6 +24,13 @@ By default, to get the corresponding database column, dbscan translates the stru
 To override this behavior, specify the column name in the `db` field tag.
 In the example above User struct is mapped to the following columns: "user_id", "first_name", "email".
 
+The translation of field names can be changed with WithFieldNameMapper option.
+Apart from SnakeCaseMapper dbscan provides LowerCamelCaseMapper, LowercaseMapper, ScreamingSnakeCaseMapper
+and KebabCaseMapper. NewSnakeCaseMapper creates a snake case mapper that keeps the provided acronyms,
+like "IPv6", as single words.
+For rules that depend on more than the field name, like the field type or its other tags,
+implement FieldMapper interface and pass it to WithFieldMapper option.
+
 If selected rows contain a column that doesn't have a corresponding struct field, dbscan returns an error,
 this forces to only select data from the database that the application needs.
 
@@ -31,6 +38,67 @@ dbscan supports commas "," in the struct tag name.
 That makes it compatible with the struct tag formats of other libraries.
 dbscan splits the tag name by "," and uses the first part as the column name.
 So `db:"user_id,other_tag_value"` struct tag is equivalent to `db:"user_id"` for dbscan.
+An empty column name, like in `db:",required"`, keeps the default column name of the field.
+
+A field can have several column aliases separated by "|", that is handy when a column is being renamed
+and both versions of the query are in use: `db:"owner_id|user_id"`.
+Rows must contain at most one of the aliases, otherwise dbscan returns an error.
+For nested structs only the first alias is used as the prefix.
+
+Struct tag options
+
+The rest of the tag parts are options, dbscan supports the following ones and ignores unknown options:
+
+	type User struct {
+		ID       string                 `db:"id,required"`       // rows must contain the column
+		Settings map[string]string      `db:"settings,json"`     // the column value is decoded as JSON
+		Age      int                    `db:"age,nullzero"`      // NULL sets the field to zero
+		Status   string                 `db:"status,default=on"` // see "Default values" below
+		Home     Address                `db:"home,inline"`       // nested columns have no prefix: "city"
+		Billing  Address                `db:",prefix=bill_"`     // nested columns: "bill_city"
+		Extra    map[string]interface{} `db:",remain"`           // columns without a corresponding field
+	}
+
+The "remain" option gives forward compatibility with queries like "SELECT *" on evolving tables:
+instead of returning an error for a column without a corresponding field, dbscan stores it into the map.
+Column values are scanned into the map element type, NULL values become zero values.
+A "remain" field of a nested struct collects only the columns with the nested struct prefix,
+the map keys don't contain the prefix.
+The "pk" option marks the field that holds the map key, see "Scanning into keyed maps" below.
+
+Use ParseTag or API.LookupTag to read tags the same way dbscan does.
+dbscan reads the `db` tag key by default, WithStructTagKeys option allows to consult several keys in order,
+for example, to fall back to `json` tags for fields without `db` tags.
+
+Default values
+
+The "default" tag option sets the value for a field when its column is absent from rows or is NULL.
+The value is parsed according to the field type, it supports strings, numbers, booleans,
+time.Duration, time.Time and types implementing encoding.TextUnmarshaler, for example:
+
+	type User struct {
+		ID      string
+		Status  string        `db:"status,default=active"`
+		Timeout time.Duration `db:"timeout,default=30s"`
+	}
+
+If the default value can't be parsed, dbscan returns an error before scanning the first row.
+Note that the default value can't contain commas.
+
+Column families
+
+Wide tables often contain families of similar columns, like "attr_color", "attr_size" or "phone_1", "phone_2".
+A column name in the struct tag that ends with "*" or "#" gathers such columns into a single field:
+
+	type Product struct {
+		ID     string
+		Attrs  map[string]string `db:"attr_*"`  // "attr_color" is stored with "color" key
+		Phones []string          `db:"phone_#"` // ordered by the numeric suffix: "phone_1", "phone_2", ...
+	}
+
+"*" matches any non-empty suffix and requires a map with string keys,
+"#" matches a numeric suffix and requires a slice.
+Columns that have a corresponding field aren't gathered.
 
 Reusing structs
 
@@ -84,6 +152,20 @@ You can also use the empty tag `db:""` to remove the prefix of a nested struct.
 UserPostComment struct is mapped to the following columns:
 "user.user_id", "user.email", "p.id", "p.text", "comment_body".
 
+Mapping types without struct tags
+
+Types from other packages, like generated types, can't have `db` tags.
+Instead, register the mapping of their fields to columns with API.RegisterMapping.
+Keys are field paths and values have the same format as the struct tag value:
+
+	err := api.RegisterMapping(sdk.Customer{}, map[string]string{
+		"CustomerID":      "id",
+		"Address.ZipCode": "zip",
+		"RawPayload":      "-",
+	})
+
+Fields that aren't listed in the mapping are mapped to columns as usual.
+
 NULLs and custom types
 
 dbscan supports custom types and NULLs perfectly.
@@ -109,6 +191,45 @@ and if the database library supports a type, dbscan supports it automatically, f
 User struct is valid, and every field will be scanned correctly, the only condition for this
 is that your database library can handle *string, CustomNullInt, CustomData and *CustomData types.
 
+dbscan also provides the generic Null type that can wrap any type to make it nullable.
+dbscan scans Null values natively, so it works for any T that your database library can scan into.
+If T is a struct, its fields are mapped to the columns prefixed with the field name,
+and the Null value is valid if at least one of these columns isn't NULL, for example:
+
+	type User struct {
+		OptionalBio dbscan.Null[string]
+		Address     dbscan.Null[Address]
+	}
+
+	type Address struct {
+		City   string
+		Street string
+	}
+
+User struct is mapped to the following columns: "optional_bio", "address.city", "address.street".
+
+Enums
+
+Go enums are often declared as integer types, while the database stores them as text.
+Register such types via WithEnum option, and dbscan will scan them from their database names:
+
+	type Status int
+
+	const (
+		StatusActive Status = iota + 1
+		StatusBlocked
+	)
+
+	api, err := dbscan.NewAPI(dbscan.WithEnum(map[Status]string{
+		StatusActive:  "active",
+		StatusBlocked: "blocked",
+	}))
+
+Alternatively, an enum type can implement the EnumScanner interface.
+Fields of enum type, pointer to enum, slice of enums and Null of enum are supported,
+slices are scanned from database arrays.
+If a column contains an unknown name, dbscan returns UnknownEnumValueError.
+
 Ignored struct fields
 
 In order for dbscan to work with a field, it must be exported. Unexported fields will be ignored.
@@ -159,6 +280,96 @@ UserPost.User.UserID will receive data from the "user_id" column and UserPost.Po
 Note that you can't access it as UserPost.UserID though. it's an error for Go, and
 you need to use the full version: UserPost.User.UserID
 
+Normalizing scanned values
+
+dbscan can post-process values after scanning, see WithTimeLocation, WithTrimCharPadding
+and WithEmptyStringAsNil options. For example, the following API converts all time values to UTC:
+
+	api, err := dbscan.NewAPI(dbscan.WithTimeLocation(time.UTC))
+
+WithTrimCharPadding trims only values of CHAR(n) columns, it requires Rows to implement ColumnTypesRows
+to tell them apart from VARCHAR and TEXT columns, sqlscan and pgxscan rows do.
+
+After scan hooks
+
+If a destination type implements AfterScanner or AfterScannerWithColumns interface,
+dbscan calls the AfterScan method after each row is scanned into it.
+Nested structs' hooks are called as well, before the hook of the destination itself.
+An error returned from AfterScan stops scanning and is propagated to the caller:
+
+	type User struct {
+		FirstName string
+		LastName  string
+		FullName  string `db:"-"`
+	}
+
+	func (u *User) AfterScan() error {
+		u.FullName = u.FirstName + " " + u.LastName
+		return nil
+	}
+
+Custom row decoding
+
+If a destination type implements RowUnmarshaler interface,
+dbscan doesn't map columns to its fields and lets the type decode the row by itself:
+
+	type Money struct {
+		amount   int64
+		currency string
+	}
+
+	func (m *Money) UnmarshalRow(columns []string, scan func(dest ...interface{}) error) error {
+		return scan(&m.amount, &m.currency)
+	}
+
+RowUnmarshaler can be nested into a struct as well,
+in that case it receives only the columns prefixed with the field column name, without the prefix:
+
+	type Order struct {
+		ID    string
+		Price Money
+	}
+
+	// Query columns: "id", "price.amount", "price.currency".
+
+Positional scanning
+
+Fields can be bound to columns by ordinal instead of names, that's useful for queries
+with aggregates or expressions that have no meaningful column names.
+A struct that embeds Positional binds its exported fields to columns in the order they are declared:
+
+	type Stats struct {
+		dbscan.Positional
+		Count int
+		Max   *time.Time
+	}
+
+	// Query: SELECT count(*), max(created_at) FROM users.
+
+In a regular struct a single field can be bound to a column by ordinal with the "#N" tag name, for example `db:"#0"`.
+Columns bound by ordinal may have duplicate names, like "count" in SELECT count(*), count(*).
+
+To scan a single row into several variables use ScanOneValues,
+it keeps the guarantees of ScanOne, but takes one destination per column:
+
+	var count int
+	var max *time.Time
+	err := dbscan.ScanOneValues(rows, &count, &max)
+
+Checking column types
+
+By default, a column that doesn't match its field type is reported by the database library
+on the first row that can't be scanned, for example only when a NULL value appears.
+With WithColumnTypeCheck option dbscan checks all columns against their struct fields before scanning,
+if Rows implement ColumnTypesRows interface, as adapters in sqlscan and pgxscan do.
+It returns IncompatibleColumnsError that lists all incompatible columns at once:
+
+	api, err := dbscan.NewAPI(dbscan.WithColumnTypeCheck(true))
+
+Nullable columns are detected only if the driver reports them, pgx and PostgreSQL drivers for database/sql don't,
+so for them the check compares only kinds of values. Only struct destinations are checked,
+see WithColumnTypeCheck for details.
+
 Scanning into map
 
 Apart from scanning into structs, dbscan can handle maps,
@@ -175,6 +386,25 @@ Map type isn't limited to map[string]interface{},
 it can be any map with a string key, e.g., map[string]string or map[string]int,
 if all column values have the same specific type.
 
+Scanning into Record
+
+Maps lose the column order, for dynamic results use Record instead.
+It contains ordered column names and values and provides typed accessors:
+
+	var records []dbscan.Record
+	dbscan.ScanAll(&records, rows)
+
+	name, err := records[0].String("name")
+	createdAt, err := records[0].Time("created_at")
+
+Record is encoded to a JSON object with keys in the column order.
+For purely positional use scan rows into Tuple, for example []dbscan.Tuple for ScanAll.
+Note that []interface{} is handled as a single column value, like an array, not as a row.
+
+Values of maps with interface{} elements, Record and Tuple are whatever the database library returns,
+unless Rows implement ValueTypesRows interface to choose the Go type for every column,
+as sqlscan does with WithColumnScanTypes option.
+
 Scanning into other types
 
 If the destination isn't a struct nor a map, dbscan handles it as a single column scan,
@@ -187,10 +417,147 @@ dbscan ensures that rows contain exactly one column and scans destination from t
 	dbscan.ScanAll(&results, rows)
 	// results variable not contains data from all rows single column.
 
+Scanning into keyed maps
+
+ScanAll accepts a map as the destination, every row becomes a map value under the key taken from the row.
+The key comes from the column set by WithKeyColumn scan option
+or from the struct field with the "pk" tag option, like `db:"id,pk"`:
+
+	var usersByID map[int64]*User
+	dbscan.ScanAll(&usersByID, rows)
+
+	var ordersByUser map[int64][]Order
+	dbscan.ScanAll(&ordersByUser, rows, dbscan.WithKeyColumn("user_id"))
+
+For a slice value, except []byte and []interface{}, rows with the same key are appended to it in the row order.
+For other values the last row wins, WithUniqueKeys scan option makes ScanAll return an error instead,
+it can't be used with slice values.
+A map with struct{} values, like map[string]struct{}, is a set, rows with a single column don't need a key option.
+The key column is passed to the value only if it has a corresponding struct field,
+so map[int64]string is a valid destination for rows with the key column and one more column.
+
+High-level functions pass scan options to ScanAll via their "With" variants:
+
+	sqlscan.SelectWith(ctx, db, &ordersByUser, query, []dbscan.ScanOption{dbscan.WithKeyColumn("user_id")})
+
+Scanning into columnar structs
+
+For analytics and charts it's handy to get a column per field rather than a struct per row.
+ScanAll accepts a pointer to a struct with slice fields as the destination,
+columns are mapped to fields by the same rules as for regular structs,
+and every field receives the values of its column from all rows:
+
+	type Series struct {
+		Day   []time.Time
+		Count []*int64
+	}
+
+	var series Series
+	dbscan.ScanAll(&series, rows)
+	// series.Day[i] and series.Count[i] are the values of the i-th row.
+
+Fields grow in lockstep, a row is appended to all of them only after it's scanned successfully.
+Element types follow the same rules as struct fields, pointers and Null[T] elements hold NULL,
+tag options like "nullzero" or "json" apply to every element.
+Before starting, ScanAll resets the slice fields. Rows must not contain columns of non slice fields.
+
+Pivot scanning
+
+ScanPivot turns narrow entity-attribute-value rows, like (entity_id, key, value), into regular structs.
+Rows are grouped by the entity column, and the value of every row goes to the struct field
+that the row key is mapped to, as if the key was a column name:
+
+	type Product struct {
+		ID    int64
+		Color string
+		Width *float64
+	}
+
+	// Rows: (1, 'color', 'red'), (1, 'width', '10.5'), (2, 'color', 'blue').
+	var products []Product
+	dbscan.ScanPivot(&products, rows, "id", "key", "value")
+	// products: [{ID: 1, Color: "red", Width: 10.5}, {ID: 2, Color: "blue"}].
+
+Values are converted into the field types, so a single textual value column can fill fields of different types.
+
+Scanning into trees
+
+ScanTree builds a tree from adjacency-list rows, like results of a recursive CTE.
+The node struct marks its id, parent and children fields with the "tree" tag option:
+
+	type Category struct {
+		ID       int64       `db:"id,tree=id"`
+		ParentID *int64      `db:"parent_id,tree=parent"`
+		Name     string
+		Children []*Category `db:",tree=children"`
+	}
+
+	var roots []*Category
+	dbscan.ScanTree(&roots, rows)
+
+Nodes with NULL or zero parent are roots. ScanTree returns *TreeOrphansError if parents of some nodes
+aren't found and *TreeCycleError if some nodes aren't reachable from the roots because they form a cycle.
+
+Capturing columns
+
+Some columns aren't part of the element, like a total count that paginated queries
+get with "COUNT(*) OVER ()" in every row. WithCapture scan option scans such a column
+of the first row into a separate variable and hides it from the element mapping:
+
+	var users []*User
+	var total int64
+	dbscan.ScanAll(&users, rows, dbscan.WithCapture("total_count", &total))
+
+If there are no rows, the variable isn't modified.
+
+Polymorphic scanning
+
+Rows of different kinds, like results of UNION ALL over several event tables, can be scanned into an interface.
+WithDiscriminator option registers the column that selects the concrete type and a factory per column value:
+
+	api, _ := dbscan.NewAPI(dbscan.WithDiscriminator("kind", map[string]func() Event{
+		"click": func() Event { return &ClickEvent{} },
+		"view":  func() Event { return &ViewEvent{} },
+	}))
+
+	var events []Event
+	api.ScanAll(&events, rows)
+
+The discriminator column is read first, then the row is scanned into the value created by the factory
+as usual, so values are scanned by the database library natively.
+It calls Rows.Scan twice for every row, which both database/sql and pgx support.
+A concrete type doesn't receive the discriminator column if it doesn't have a field for it,
+as well as columns that only other concrete types have fields for,
+so every type can ignore columns of the others. Plans are cached by the API per concrete type.
+If the column value doesn't have a factory, *UnknownDiscriminatorError is returned.
+
+Sparse fieldsets
+
+To fetch only some fields of a model, like for ?fields= parameters of an API,
+Columns returns the columns of the selected fields, it uses the same mapping as scanning:
+
+	columns, _ := dbscan.Columns(User{}, "Name", "Address.City")
+	// columns: ["name", "address.city"]
+	query := "SELECT " + strings.Join(dbscan.QuoteColumns(columns), ", ") + " FROM users_view"
+	// query: SELECT "name", "address.city" FROM users_view
+
+Nested columns contain the separator, so QuoteColumns quotes every column as a whole,
+for joined tables alias the source columns instead, like a.city AS "address.city".
+
+	var users []*User
+	dbscan.ScanAll(&users, rows, dbscan.WithSparseFields(true))
+
+A path to a nested struct selects all its columns. Other fields keep their zero or default values,
+WithSparseFields scan option makes ScanAll and ScanOne accept rows without columns of fields with "required" tag option,
+high-level functions pass it via SelectWith and GetWith, like sqlscan.GetWith.
+Fields that receive columns depending on the rows, like fields with "remain" tag option, column patterns
+or RowUnmarshaler fields, can't be listed, Columns returns an error for them.
+
 Duplicate columns
 
 Rows must not contain duplicate columns otherwise, dbscan won't be able to decide
 from which column to select and will return an error.
+Columns bound to struct fields by ordinal are an exception, see Positional scanning.
 
 Support for Row type
 
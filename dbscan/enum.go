@@ -0,0 +1,259 @@
+package dbscan
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// EnumScanner can be implemented by enum types to be scanned from their database names
+// without registering them via WithEnum option.
+// The method must have a pointer receiver.
+type EnumScanner interface {
+	// ScanEnum sets the enum value by its database name, it reports false if the name is unknown.
+	ScanEnum(name string) bool
+}
+
+var enumScannerType = reflect.TypeOf((*EnumScanner)(nil)).Elem()
+
+// UnknownEnumValueError is returned when a column contains a value that doesn't correspond to any value of the enum.
+type UnknownEnumValueError struct {
+	Column string
+	Type   reflect.Type
+	Value  string
+}
+
+// Error implements the error interface.
+func (e *UnknownEnumValueError) Error() string {
+	return fmt.Sprintf("scany: column: '%s': unknown value %q for enum type %v", e.Column, e.Value, e.Type)
+}
+
+type enumOption struct {
+	enumType reflect.Type
+	names    map[interface{}]string
+}
+
+// WithEnum registers an enum type, so dbscan can scan values of this type from database text columns.
+// names maps every enum value to its database name, for example:
+//
+//	type Status int
+//
+//	const (
+//	    StatusActive Status = iota + 1
+//	    StatusBlocked
+//	)
+//
+//	dbscan.WithEnum(map[Status]string{
+//	    StatusActive:  "active",
+//	    StatusBlocked: "blocked",
+//	})
+//
+// Fields of type Status, *Status, []Status and Null[Status] are scanned from the database names.
+// Slices are scanned from database arrays.
+func WithEnum[T comparable](names map[T]string) APIOption {
+	opt := &enumOption{
+		enumType: reflect.TypeOf((*T)(nil)).Elem(),
+		names:    make(map[interface{}]string, len(names)),
+	}
+	for v, name := range names {
+		opt.names[v] = name
+	}
+	return func(api *API) {
+		api.enumOptions = append(api.enumOptions, opt)
+	}
+}
+
+func (api *API) registerEnums() error {
+	api.enums = make(map[reflect.Type]map[string]reflect.Value, len(api.enumOptions))
+	for _, opt := range api.enumOptions {
+		if _, exists := api.enums[opt.enumType]; exists {
+			return fmt.Errorf("scany: enum type %v is registered more than once", opt.enumType)
+		}
+		values := make(map[string]reflect.Value, len(opt.names))
+		for v, name := range opt.names {
+			if _, exists := values[name]; exists {
+				return fmt.Errorf("scany: enum type %v: name %q is used for more than one value", opt.enumType, name)
+			}
+			values[name] = reflect.ValueOf(v)
+		}
+		api.enums[opt.enumType] = values
+	}
+	return nil
+}
+
+// enumParser sets the enum value by its name, it reports false if the name is unknown.
+type enumParser func(dst reflect.Value, name string) bool
+
+func (api *API) getEnumParser(t reflect.Type) enumParser {
+	if values, ok := api.enums[t]; ok {
+		return func(dst reflect.Value, name string) bool {
+			v, ok := values[name]
+			if ok {
+				dst.Set(v)
+			}
+			return ok
+		}
+	}
+	if reflect.PtrTo(t).Implements(enumScannerType) {
+		return func(dst reflect.Value, name string) bool {
+			return dst.Addr().Interface().(EnumScanner).ScanEnum(name)
+		}
+	}
+	return nil
+}
+
+// enumScanTarget returns the destination for Rows.Scan if value is an enum, a pointer to an enum,
+// a slice of enums or Null of an enum. Otherwise, it returns a nil finish function.
+// NULL resets non-pointer enums to zero.
+func (api *API) enumScanTarget(value reflect.Value, column string) (interface{}, finishScanFunc) {
+	valueType := value.Type()
+	switch {
+	case isNullType(valueType):
+		nullValue := value
+		value := nullValue.Field(nullValueFieldIndex)
+		parse := api.getEnumParser(value.Type())
+		if parse == nil {
+			return nil, nil
+		}
+		name := new(*string)
+		return name, func() (bool, error) {
+			valid := *name != nil
+			nullValue.Field(nullValidFieldIndex).SetBool(valid)
+			return valid, assignEnum(value, parse, *name, column)
+		}
+	case valueType.Kind() == reflect.Slice:
+		parse := api.getEnumParser(valueType.Elem())
+		if parse == nil {
+			return nil, nil
+		}
+		names := new(enumNames)
+		return names, func() (bool, error) {
+			if *names == nil {
+				value.Set(reflect.Zero(valueType))
+				return false, nil
+			}
+			slice := reflect.MakeSlice(valueType, len(*names), len(*names))
+			for i, name := range *names {
+				name := name
+				if err := assignEnum(slice.Index(i), parse, &name, column); err != nil {
+					return false, err
+				}
+			}
+			value.Set(slice)
+			return true, nil
+		}
+	default:
+		enumValue := value
+		if valueType.Kind() == reflect.Ptr {
+			enumValue = reflect.New(valueType.Elem()).Elem()
+		}
+		parse := api.getEnumParser(enumValue.Type())
+		if parse == nil {
+			return nil, nil
+		}
+		name := new(*string)
+		return name, func() (bool, error) {
+			if *name == nil {
+				value.Set(reflect.Zero(valueType))
+				return false, nil
+			}
+			if err := assignEnum(enumValue, parse, *name, column); err != nil {
+				return false, err
+			}
+			if valueType.Kind() == reflect.Ptr {
+				value.Set(enumValue.Addr())
+			}
+			return true, nil
+		}
+	}
+}
+
+func assignEnum(dst reflect.Value, parse enumParser, name *string, column string) error {
+	if name == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	if !parse(dst, *name) {
+		return &UnknownEnumValueError{Column: column, Type: dst.Type(), Value: *name}
+	}
+	return nil
+}
+
+// enumNames is the destination for database arrays of enums.
+// Its underlying type allows pgx to scan arrays natively,
+// for other libraries it implements sql.Scanner that parses the postgres array text representation.
+type enumNames []string
+
+// Scan implements the sql.Scanner interface.
+func (en *enumNames) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*en = nil
+	case []string:
+		*en = append(enumNames{}, v...)
+	case []interface{}:
+		names := make(enumNames, len(v))
+		for i, e := range v {
+			name, ok := e.(string)
+			if !ok {
+				return fmt.Errorf("scany: unsupported enum array element type %T", e)
+			}
+			names[i] = name
+		}
+		*en = names
+	case []byte:
+		return en.Scan(string(v))
+	case string:
+		names, err := parseArray(v)
+		if err != nil {
+			return err
+		}
+		*en = names
+	default:
+		return fmt.Errorf("scany: unsupported enum array type %T", src)
+	}
+	return nil
+}
+
+// parseArray parses a one-dimensional postgres array text representation, like {a,"b c"}.
+func parseArray(s string) ([]string, error) {
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return nil, fmt.Errorf("scany: invalid array %q", s)
+	}
+	s = s[1 : len(s)-1]
+	elements := []string{}
+	if s == "" {
+		return elements, nil
+	}
+	var element strings.Builder
+	var quoted, wasQuoted, escaped bool
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case escaped:
+			element.WriteByte(c)
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+			wasQuoted = true
+		case c == ',' && !quoted:
+			if !wasQuoted && element.String() == "NULL" {
+				return nil, fmt.Errorf("scany: array %q contains NULL element", s)
+			}
+			elements = append(elements, element.String())
+			element.Reset()
+			wasQuoted = false
+		case c == '{' && !quoted:
+			return nil, fmt.Errorf("scany: multidimensional array %q isn't supported", s)
+		default:
+			element.WriteByte(c)
+		}
+	}
+	if !wasQuoted && element.String() == "NULL" {
+		return nil, fmt.Errorf("scany: array %q contains NULL element", s)
+	}
+	elements = append(elements, element.String())
+	return elements, nil
+}
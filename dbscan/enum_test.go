@@ -0,0 +1,188 @@
+package dbscan_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/georgysavva/scany/v2/dbscan"
+)
+
+type testStatus int
+
+const (
+	testStatusActive testStatus = iota + 1
+	testStatusBlocked
+)
+
+var testStatusNames = map[testStatus]string{
+	testStatusActive:  "active",
+	testStatusBlocked: "blocked",
+}
+
+type testColor int
+
+func (c *testColor) ScanEnum(name string) bool {
+	switch name {
+	case "red":
+		*c = 1
+	case "green":
+		*c = 2
+	default:
+		return false
+	}
+	return true
+}
+
+func TestRowScanner_Scan_enumDestination(t *testing.T) {
+	t.Parallel()
+	blocked := testStatusBlocked
+	cases := []struct {
+		name     string
+		query    string
+		expected interface{}
+	}{
+		{
+			name: "registered enum fields",
+			query: `
+				SELECT 'active' AS foo, 'blocked' AS bar, NULL AS baz
+			`,
+			expected: struct {
+				Foo testStatus
+				Bar *testStatus
+				Baz *testStatus
+			}{
+				Foo: testStatusActive,
+				Bar: &blocked,
+				Baz: nil,
+			},
+		},
+		{
+			name: "enum slice field is filled from array",
+			query: `
+				SELECT ARRAY['active', 'blocked'] AS foo
+			`,
+			expected: struct {
+				Foo []testStatus
+			}{
+				Foo: []testStatus{testStatusActive, testStatusBlocked},
+			},
+		},
+		{
+			name: "Null enum field",
+			query: `
+				SELECT 'active' AS foo, NULL AS bar
+			`,
+			expected: struct {
+				Foo dbscan.Null[testStatus]
+				Bar dbscan.Null[testStatus]
+			}{
+				Foo: dbscan.NewNull(testStatusActive),
+				Bar: dbscan.Null[testStatus]{},
+			},
+		},
+		{
+			name: "enum field default value is parsed from name",
+			query: `
+				SELECT NULL AS foo
+			`,
+			expected: struct {
+				Foo testStatus `db:"foo,default=blocked"`
+			}{
+				Foo: testStatusBlocked,
+			},
+		},
+		{
+			name: "EnumScanner field",
+			query: `
+				SELECT 'green' AS foo
+			`,
+			expected: struct {
+				Foo testColor
+			}{
+				Foo: 2,
+			},
+		},
+		{
+			name: "enum primitive type",
+			query: `
+				SELECT 'blocked' AS foo
+			`,
+			expected: testStatusBlocked,
+		},
+	}
+	api, err := getAPI(dbscan.WithEnum(testStatusNames))
+	require.NoError(t, err)
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rows := queryRows(t, tc.query)
+			defer rows.Close() //nolint: errcheck
+			dst := allocateDestination(tc.expected)
+			rows.Next()
+			err := api.ScanRow(dst, rows)
+			require.NoError(t, err)
+			requireNoRowsErrorsAndClose(t, rows)
+			assertDestinationEqual(t, tc.expected, dst)
+		})
+	}
+}
+
+func TestRowScanner_Scan_unknownEnumValue_returnsErr(t *testing.T) {
+	t.Parallel()
+	api, err := getAPI(dbscan.WithEnum(testStatusNames))
+	require.NoError(t, err)
+	query := `
+		SELECT 'foo val' AS foo
+	`
+	rows := queryRows(t, query)
+	defer rows.Close() //nolint: errcheck
+	rows.Next()
+
+	dst := &struct{ Foo testStatus }{}
+	err = api.ScanRow(dst, rows)
+
+	var enumErr *dbscan.UnknownEnumValueError
+	require.True(t, errors.As(err, &enumErr))
+	assert.Equal(t, "foo", enumErr.Column)
+	assert.Equal(t, "foo val", enumErr.Value)
+	assert.EqualError(t, err, "doing scan: scanFn: scany: scan row into struct fields: "+
+		"scany: column: 'foo': unknown value \"foo val\" for enum type dbscan_test.testStatus")
+}
+
+func TestNewAPI_WithEnum_InvalidInput(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name        string
+		opts        []dbscan.APIOption
+		expectedErr string
+	}{
+		{
+			name: "duplicate name",
+			opts: []dbscan.APIOption{
+				dbscan.WithEnum(map[testStatus]string{testStatusActive: "active", testStatusBlocked: "active"}),
+			},
+			expectedErr: "scany: enum type dbscan_test.testStatus: name \"active\" is used for more than one value",
+		},
+		{
+			name: "duplicate registration",
+			opts: []dbscan.APIOption{
+				dbscan.WithEnum(testStatusNames),
+				dbscan.WithEnum(testStatusNames),
+			},
+			expectedErr: "scany: enum type dbscan_test.testStatus is registered more than once",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			api, err := dbscan.NewAPI(tc.opts...)
+			assert.EqualError(t, err, tc.expectedErr)
+			assert.Nil(t, api)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package dbscan
+
+import (
+	"reflect"
+	"sort"
+)
+
+// hiddenColumnsRows hides some columns of the rows from the scanner, they are scanned separately,
+// like the key column of keyed maps or captured columns.
+type hiddenColumnsRows struct {
+	Rows
+	// hidden contains positions of the hidden columns in ascending order.
+	hidden []int
+	// targets contains scan targets for the hidden columns, in the order of hidden positions,
+	// the value of the hidden column is discarded if it doesn't have a target.
+	// It allows to scan hidden columns along with the rest of the row in a single Rows.Scan call.
+	targets []interface{}
+}
+
+var (
+	_ ValueTypesRows  = &hiddenColumnsRows{}
+	_ ColumnTypesRows = &hiddenColumnsRows{}
+)
+
+func newHiddenColumnsRows(rows Rows, hidden []int) *hiddenColumnsRows {
+	hidden = append([]int(nil), hidden...)
+	sort.Ints(hidden)
+	return &hiddenColumnsRows{Rows: rows, hidden: hidden, targets: make([]interface{}, len(hidden))}
+}
+
+func (r *hiddenColumnsRows) Columns() ([]string, error) {
+	columns, err := r.Rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	return hideColumns(columns, r.hidden), nil
+}
+
+func (r *hiddenColumnsRows) Scan(dest ...interface{}) error {
+	scans := make([]interface{}, 0, len(dest)+len(r.hidden))
+	for i := 0; len(dest) > 0 || i < len(r.hidden); {
+		if i < len(r.hidden) && r.hidden[i] == len(scans) {
+			target := r.targets[i]
+			if target == nil {
+				target = &Discard{}
+			}
+			scans = append(scans, target)
+			i++
+			continue
+		}
+		if len(dest) == 0 {
+			// Rows contain fewer columns than hidden positions, let the underlying rows report it.
+			break
+		}
+		scans = append(scans, dest[0])
+		dest = dest[1:]
+	}
+	return r.Rows.Scan(scans...)
+}
+
+func (r *hiddenColumnsRows) ValueTypes() ([]reflect.Type, error) {
+	typedRows, ok := r.Rows.(ValueTypesRows)
+	if !ok {
+		columns, err := r.Columns()
+		return make([]reflect.Type, len(columns)), err
+	}
+	valueTypes, err := typedRows.ValueTypes()
+	if err != nil {
+		return nil, err
+	}
+	return hideColumns(valueTypes, r.hidden), nil
+}
+
+func (r *hiddenColumnsRows) ColumnTypes() ([]ColumnType, error) {
+	typedRows, ok := r.Rows.(ColumnTypesRows)
+	if !ok {
+		columns, err := r.Columns()
+		columnTypes := make([]ColumnType, len(columns))
+		for i, column := range columns {
+			columnTypes[i].Name = column
+		}
+		return columnTypes, err
+	}
+	columnTypes, err := typedRows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	return hideColumns(columnTypes, r.hidden), nil
+}
+
+// hideColumns returns values without the elements at the hidden positions.
+func hideColumns[T any](values []T, hidden []int) []T {
+	result := make([]T, 0, len(values))
+	for i, v := range values {
+		if j := sort.SearchInts(hidden, i); j < len(hidden) && hidden[j] == i {
+			continue
+		}
+		result = append(result, v)
+	}
+	return result
+}
@@ -0,0 +1,136 @@
+package dbscan
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// AfterScanner can be implemented by a destination type to be notified after a row is scanned into it.
+// It's useful to compute derived fields, validate invariants or decode values.
+// If AfterScan returns an error, scanning stops and the error is propagated to the caller.
+//
+// dbscan calls AfterScan for the destination itself and all nested structs filled from the row,
+// hooks of nested structs are called first. Nested structs follow the struct mapping:
+// structs ignored with "-" in the tag, the field mapper or RegisterMapping are skipped,
+// while JSON and RowUnmarshaler fields are included.
+// Embedded structs are an exception,
+// their hooks are promoted to the embedding struct by Go and aren't called separately.
+// Nested structs by a nil pointer and invalid Null structs are skipped.
+type AfterScanner interface {
+	AfterScan() error
+}
+
+// AfterScannerWithColumns is the same as AfterScanner,
+// but it also receives all columns of the scanned row.
+type AfterScannerWithColumns interface {
+	AfterScan(columns []string) error
+}
+
+var (
+	afterScannerType            = reflect.TypeOf((*AfterScanner)(nil)).Elem()
+	afterScannerWithColumnsType = reflect.TypeOf((*AfterScannerWithColumns)(nil)).Elem()
+)
+
+func implementsAfterScan(t reflect.Type) bool {
+	ptrType := reflect.PtrTo(t)
+	return ptrType.Implements(afterScannerType) || ptrType.Implements(afterScannerWithColumnsType)
+}
+
+// afterScanHooks returns indexes of nested structs that implement one of the after scan interfaces.
+// Only structs filled from the row are included: nested structs traversed by the mapping,
+// as well as JSON and RowUnmarshaler fields that are decoded as a whole. Deeper structs go first.
+func (m *structMapping) afterScanHooks(structType reflect.Type) [][]int {
+	var hooks [][]int
+	for _, ns := range m.structs {
+		if implementsAfterScan(ns.Type) {
+			hooks = append(hooks, ns.Index)
+		}
+	}
+	seen := make(map[*fieldMeta]struct{}, len(m.columns))
+	for _, field := range m.columns {
+		if _, ok := seen[field]; ok || !field.JSON && !field.RowUnmarshaler {
+			continue
+		}
+		seen[field] = struct{}{}
+		if index, ok := decodedStructIndex(structType, field); ok {
+			hooks = append(hooks, index)
+		}
+	}
+	sort.SliceStable(hooks, func(i, j int) bool {
+		if len(hooks[i]) != len(hooks[j]) {
+			return len(hooks[i]) > len(hooks[j])
+		}
+		return lessIndex(hooks[i], hooks[j])
+	})
+	return hooks
+}
+
+// decodedStructIndex returns the index of the struct that a JSON or RowUnmarshaler field is decoded into,
+// it reports false if the struct doesn't implement the after scan interfaces.
+func decodedStructIndex(structType reflect.Type, field *fieldMeta) ([]int, bool) {
+	index := field.Index
+	t := structType.FieldByIndex(index).Type
+	if isNullType(t) {
+		index = append(index[:len(index):len(index)], nullValueFieldIndex)
+		t = t.Field(nullValueFieldIndex).Type
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return index, t.Kind() == reflect.Struct && implementsAfterScan(t)
+}
+
+func (rs *RowScanner) runAfterScanHooks(dstValue reflect.Value) error {
+	for _, index := range rs.afterScanHooks {
+		nested, ok := nestedValue(dstValue, index)
+		if !ok {
+			continue
+		}
+		if err := callAfterScan(nested, rs.columns); err != nil {
+			return err
+		}
+	}
+	if dstValue.CanAddr() && implementsAfterScan(dstValue.Type()) {
+		return callAfterScan(dstValue, rs.columns)
+	}
+	return nil
+}
+
+func callAfterScan(v reflect.Value, columns []string) error {
+	var err error
+	switch hook := v.Addr().Interface().(type) {
+	case AfterScanner:
+		err = hook.AfterScan()
+	case AfterScannerWithColumns:
+		err = hook.AfterScan(columns)
+	}
+	if err != nil {
+		return fmt.Errorf("scany: after scan hook of %v: %w", v.Type(), err)
+	}
+	return nil
+}
+
+// nestedValue returns the nested struct value by its index.
+// It reports false if the struct is behind a nil pointer or inside an invalid Null.
+func nestedValue(v reflect.Value, index []int) (reflect.Value, bool) {
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		if isNullType(v.Type()) && !v.Field(nullValidFieldIndex).Bool() {
+			return reflect.Value{}, false
+		}
+		v = v.Field(i)
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
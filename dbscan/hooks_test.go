@@ -0,0 +1,123 @@
+package dbscan_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type hookNested struct {
+	Foo      string
+	FooUpper string `db:"-"`
+}
+
+func (hn *hookNested) AfterScan() error {
+	if hn.Foo == "invalid" {
+		return errors.New("invalid foo")
+	}
+	hn.FooUpper = strings.ToUpper(hn.Foo)
+	return nil
+}
+
+type hookModel struct {
+	Bar         string
+	Nested      hookNested
+	NestedByPtr *hookNested
+	Columns     []string `db:"-"`
+	Summary     string   `db:"-"`
+}
+
+func (hm *hookModel) AfterScan(columns []string) error {
+	hm.Columns = columns
+	// Nested hooks are called first, so FooUpper is already computed.
+	hm.Summary = hm.Bar + " " + hm.Nested.FooUpper
+	return nil
+}
+
+func TestScanAll_afterScanHooks(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT *
+		FROM (
+			VALUES ('foo val', 'bar val'), ('foo val 2', 'bar val 2')
+		) AS t ("nested.foo", bar)
+	`
+	rows := queryRows(t, query)
+	columns := []string{"nested.foo", "bar"}
+	expected := []*hookModel{
+		{
+			Bar:     "bar val",
+			Nested:  hookNested{Foo: "foo val", FooUpper: "FOO VAL"},
+			Columns: columns,
+			Summary: "bar val FOO VAL",
+		},
+		{
+			Bar:     "bar val 2",
+			Nested:  hookNested{Foo: "foo val 2", FooUpper: "FOO VAL 2"},
+			Columns: columns,
+			Summary: "bar val 2 FOO VAL 2",
+		},
+	}
+
+	var got []*hookModel
+	err := testAPI.ScanAll(&got, rows)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
+
+func TestScanOne_afterScanHookReturnsErr_propagatesErr(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT 'invalid' AS "nested_by_ptr.foo"
+	`
+	rows := queryRows(t, query)
+	expectedErr := "scanning: doing scan: after scan: scany: after scan hook of dbscan_test.hookNested: invalid foo"
+
+	var got hookModel
+	err := testAPI.ScanOne(&got, rows)
+
+	assert.EqualError(t, err, expectedErr)
+}
+
+type hookCounter struct {
+	Foo    string
+	Called bool `db:"-"`
+}
+
+func (hc *hookCounter) AfterScan() error {
+	hc.Called = true
+	return nil
+}
+
+func TestScanOne_afterScanHooks_followMapping(t *testing.T) {
+	t.Parallel()
+	type model struct {
+		Ignored  hookCounter `db:"-"`
+		Mapped   hookCounter `db:"-"`
+		Excluded hookCounter
+	}
+	api, err := getAPI()
+	require.NoError(t, err)
+	err = api.RegisterMapping(model{}, map[string]string{
+		"Mapped":   "mapped",
+		"Excluded": "-",
+	})
+	require.NoError(t, err)
+	query := `
+		SELECT 'foo val' AS "mapped.foo"
+	`
+	rows := queryRows(t, query)
+	expected := model{
+		Mapped: hookCounter{Foo: "foo val", Called: true},
+	}
+
+	var got model
+	err = api.ScanOne(&got, rows)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
@@ -25,7 +25,9 @@ func DoTestRowScannerStartCalledExactlyOnce(t *testing.T, api *API, queryRows qu
 	mockStart.On("Execute", rs, mock.AnythingOfType("reflect.Value")).Return(nil).Run(func(args mock.Arguments) {
 		rs := args.Get(0).(*RowScanner)
 		rs.columns = []string{"foo", "bar"}
-		rs.columnToFieldIndex = map[string][]int{"foo": {0}, "bar": {1}}
+		foo, bar := &fieldMeta{Index: []int{0}}, &fieldMeta{Index: []int{1}}
+		rs.columnToFieldIndex = map[string]*fieldMeta{"foo": foo, "bar": bar}
+		rs.columnFields = []*fieldMeta{foo, bar}
 		rs.scanFn = rs.scanStruct
 	})
 
@@ -0,0 +1,279 @@
+package dbscan
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// keyedMode is the way ScanAll stores rows into a map destination.
+type keyedMode int
+
+const (
+	// keyedValues stores a single value per key, like map[K]T.
+	keyedValues keyedMode = iota
+	// keyedGroups appends values with the same key to a slice, like map[K][]T.
+	keyedGroups
+	// keyedSet stores only keys, like map[K]struct{}.
+	keyedSet
+)
+
+type mapDestinationMeta struct {
+	val             reflect.Value
+	mode            keyedMode
+	elementBaseType reflect.Type
+	elementByPtr    bool
+	uniqueKeys      bool
+	// keyPosition is the index of the key column, it's -1 if keys are taken from the field with the pk tag option.
+	keyPosition int
+	keyColumn   string
+	// pkIndex is the index sequence of the field that keys are taken from:
+	// the field with the pk tag option or the field of the key column.
+	pkIndex []int
+	// valueRows are the rows that values are scanned from,
+	// they don't contain the key column if the value doesn't have a field for it.
+	valueRows Rows
+	// keyRows is set if the key column is hidden from values, it scans the key along with the value.
+	keyRows *hiddenColumnsRows
+}
+
+func isMapDestination(dst interface{}) bool {
+	dstType := reflect.TypeOf(dst)
+	return dstType != nil && dstType.Kind() == reflect.Ptr && dstType.Elem().Kind() == reflect.Map
+}
+
+func (api *API) parseMapDestination(dst interface{}, rows Rows, cfg *scanConfig) (*mapDestinationMeta, error) {
+	dstValue, err := parseDestination(dst)
+	if err != nil {
+		return nil, fmt.Errorf("scany: parsing destination: %w", err)
+	}
+	meta := &mapDestinationMeta{val: dstValue, uniqueKeys: cfg.uniqueKeys, keyPosition: -1, valueRows: rows}
+	valueType := dstValue.Type().Elem()
+	switch {
+	case valueType.Kind() == reflect.Struct && valueType.NumField() == 0:
+		meta.mode = keyedSet
+	case api.isGroupType(valueType):
+		if cfg.uniqueKeys {
+			return nil, fmt.Errorf("scany: WithUniqueKeys scan option can't be used with %v destination, "+
+				"it collects all rows with the same key", dstValue.Type())
+		}
+		meta.mode = keyedGroups
+		valueType = valueType.Elem()
+	}
+	if meta.mode != keyedSet {
+		meta.elementBaseType, meta.elementByPtr = api.elementBaseType(valueType)
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("scany: get rows columns: %w", err)
+	}
+	if err := api.setKeySource(meta, columns, cfg.keyColumn); err != nil {
+		return nil, err
+	}
+	if meta.keyPosition >= 0 && meta.mode != keyedSet {
+		if err := api.setKeyRows(meta, rows, columns[meta.keyPosition]); err != nil {
+			return nil, err
+		}
+	}
+	// Make sure map is empty.
+	dstValue.Set(reflect.MakeMap(dstValue.Type()))
+	return meta, nil
+}
+
+// isGroupType reports whether the map value type is a slice that collects all rows with the same key.
+// Slices that hold a single column value or the whole row, like []byte, []interface{} or Tuple, aren't groups.
+func (api *API) isGroupType(valueType reflect.Type) bool {
+	return valueType.Kind() == reflect.Slice && valueType.Elem().Kind() != reflect.Uint8 &&
+		valueType != interfaceSliceType && valueType != tupleType &&
+		!api.isScannableType(valueType) && !implementsRowUnmarshaler(valueType)
+}
+
+// setKeySource finds out where map keys are taken from:
+// the key column, the field with the pk tag option or the only column for sets.
+func (api *API) setKeySource(meta *mapDestinationMeta, columns []string, keyColumn string) error {
+	if keyColumn != "" {
+		meta.keyPosition = columnPosition(columns, keyColumn)
+		if meta.keyPosition >= 0 {
+			return nil
+		}
+		return fmt.Errorf("scany: key column '%s' isn't found in rows", keyColumn)
+	}
+	if meta.mode != keyedSet && api.isStructValue(meta.elementBaseType) {
+		pk, err := api.getPrimaryKeyField(meta.elementBaseType)
+		if err != nil {
+			return err
+		}
+		if pk != nil {
+			meta.pkIndex = pk.Index
+			return nil
+		}
+	}
+	if meta.mode == keyedSet && len(columns) == 1 {
+		meta.keyPosition = 0
+		return nil
+	}
+	return fmt.Errorf("scany: map key isn't specified, use WithKeyColumn scan option or %q tag option", TagOptionPK)
+}
+
+// setKeyRows makes sure the key column is scanned once per row along with the value.
+// If the value has a field for the key column, the key is taken from that field,
+// otherwise the key column is hidden from the value and scanned into the key directly.
+func (api *API) setKeyRows(meta *mapDestinationMeta, rows Rows, column string) error {
+	meta.keyColumn = column
+	if api.isStructValue(meta.elementBaseType) && !isPositionalStruct(meta.elementBaseType) {
+		mapping, err := api.getStructMapping(meta.elementBaseType)
+		if err != nil {
+			return fmt.Errorf("scany: map columns to fields of %v: %w", meta.elementBaseType, err)
+		}
+		if field, ok := mapping.columns[column]; ok && !field.RowUnmarshaler {
+			meta.pkIndex = field.Index
+			meta.keyPosition = -1
+			return nil
+		}
+	}
+	hasKey, err := api.hasColumn(meta.elementBaseType, column)
+	if err != nil {
+		return err
+	}
+	if !hasKey {
+		meta.keyRows = newHiddenColumnsRows(rows, []int{meta.keyPosition})
+		meta.valueRows = meta.keyRows
+		meta.keyPosition = -1
+	}
+	return nil
+}
+
+// isStructValue reports whether columns are mapped to fields of the type.
+func (api *API) isStructValue(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != recordType && !api.isScannableType(t) && !implementsRowUnmarshaler(t)
+}
+
+func (api *API) getPrimaryKeyField(structType reflect.Type) (*fieldMeta, error) {
+	mapping, err := api.getStructMapping(structType)
+	if err != nil {
+		return nil, fmt.Errorf("scany: map columns to fields of %v: %w", structType, err)
+	}
+	var pk *fieldMeta
+	check := func(field *fieldMeta) error {
+		if !field.PrimaryKey || field == pk {
+			return nil
+		}
+		if pk != nil {
+			return fmt.Errorf("scany: %v has more than one field with %q tag option", structType, TagOptionPK)
+		}
+		pk = field
+		return nil
+	}
+	for _, field := range mapping.columns {
+		if err := check(field); err != nil {
+			return nil, err
+		}
+	}
+	for _, field := range mapping.positions {
+		if err := check(field); err != nil {
+			return nil, err
+		}
+	}
+	return pk, nil
+}
+
+// hasColumn reports whether values of the type receive the column.
+func (api *API) hasColumn(t reflect.Type, column string) (bool, error) {
+	columns, all, err := api.receivedColumns(t)
+	if err != nil || all {
+		return all, err
+	}
+	_, ok := columns[column]
+	return ok, nil
+}
+
+// receivedColumns returns the columns that values of the type receive by name,
+// all is true if they receive every column of the row, like maps or Record.
+func (api *API) receivedColumns(t reflect.Type) (columns map[string]*fieldMeta, all bool, err error) {
+	switch {
+	case t == recordType || t == tupleType || t.Kind() == reflect.Map || implementsRowUnmarshaler(t):
+		return nil, true, nil
+	case api.isStructValue(t) && !isPositionalStruct(t):
+		mapping, err := api.getStructMapping(t)
+		if err != nil {
+			return nil, false, fmt.Errorf("scany: map columns to fields of %v: %w", t, err)
+		}
+		return mapping.columns, false, nil
+	default:
+		return nil, false, nil
+	}
+}
+
+func (api *API) scanMapElement(rs *RowScanner, rows Rows, meta *mapDestinationMeta) error {
+	key := reflect.New(meta.val.Type().Key()).Elem()
+	// The key column is scanned separately only for sets and values that receive the whole row,
+	// like maps or RowUnmarshaler types, the key can't be taken from them.
+	if meta.keyPosition >= 0 {
+		if err := api.scanKey(rows, meta.keyPosition, key); err != nil {
+			return err
+		}
+	}
+	if meta.mode == keyedSet {
+		return meta.store(key, reflect.Zero(meta.val.Type().Elem()))
+	}
+	var finishKey finishScanFunc
+	if meta.keyRows != nil {
+		meta.keyRows.targets[0], finishKey = api.scanTarget(key, meta.keyColumn, false /* nullable */)
+	}
+	dstValPtr := reflect.New(meta.elementBaseType)
+	if err := rs.Scan(dstValPtr.Interface()); err != nil {
+		return fmt.Errorf("scanning: %w", err)
+	}
+	if finishKey != nil {
+		if _, err := finishKey(); err != nil {
+			return fmt.Errorf("scany: scan map key: %w", err)
+		}
+	}
+	if meta.pkIndex != nil {
+		pkValue, ok := nestedValue(dstValPtr.Elem(), meta.pkIndex)
+		if !ok {
+			return fmt.Errorf("scany: map key field is nil")
+		}
+		if err := assignValue(key, pkValue.Interface()); err != nil {
+			return fmt.Errorf("scany: map key: %w", err)
+		}
+	}
+	if meta.elementByPtr {
+		return meta.store(key, dstValPtr)
+	}
+	return meta.store(key, dstValPtr.Elem())
+}
+
+// scanKey scans the key column of the current row into the key value.
+func (api *API) scanKey(rows Rows, position int, key reflect.Value) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("scany: get rows columns: %w", err)
+	}
+	scans := discardScanTargets(len(columns))
+	var finish finishScanFunc
+	scans[position], finish = api.scanTarget(key, columns[position], false /* nullable */)
+	if err := rows.Scan(scans...); err != nil {
+		return fmt.Errorf("scany: scan map key: %w", err)
+	}
+	if finish != nil {
+		if _, err := finish(); err != nil {
+			return fmt.Errorf("scany: scan map key: %w", err)
+		}
+	}
+	return nil
+}
+
+func (meta *mapDestinationMeta) store(key, value reflect.Value) error {
+	existing := meta.val.MapIndex(key)
+	if existing.IsValid() && meta.uniqueKeys {
+		return fmt.Errorf("scany: duplicate map key: %v", key.Interface())
+	}
+	if meta.mode == keyedGroups {
+		if !existing.IsValid() {
+			existing = reflect.Zero(meta.val.Type().Elem())
+		}
+		value = reflect.Append(existing, value)
+	}
+	meta.val.SetMapIndex(key, value)
+	return nil
+}
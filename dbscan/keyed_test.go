@@ -0,0 +1,174 @@
+package dbscan_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/georgysavva/scany/v2/dbscan"
+)
+
+const keyedRowsQuery = `
+	SELECT *
+	FROM (
+		VALUES (1::INT8, 10::INT8, 'foo val'), (1, 11, 'foo val 2'), (2, 12, 'foo val 3')
+	) AS t (user_id, id, foo)
+`
+
+func TestScanAll_keyedMap(t *testing.T) {
+	t.Parallel()
+	type order struct {
+		ID  int64
+		Foo string
+	}
+	type orderWithPK struct {
+		UserID int64
+		ID     int64 `db:",pk"`
+		Foo    string
+	}
+	cases := []struct {
+		name     string
+		dst      interface{}
+		opts     []dbscan.ScanOption
+		expected interface{}
+	}{
+		{
+			name: "groups by key column",
+			dst:  &map[int64][]order{},
+			opts: []dbscan.ScanOption{dbscan.WithKeyColumn("user_id")},
+			expected: &map[int64][]order{
+				1: {{ID: 10, Foo: "foo val"}, {ID: 11, Foo: "foo val 2"}},
+				2: {{ID: 12, Foo: "foo val 3"}},
+			},
+		},
+		{
+			name: "values by pk field",
+			dst:  &map[int64]*orderWithPK{},
+			expected: &map[int64]*orderWithPK{
+				10: {UserID: 1, ID: 10, Foo: "foo val"},
+				11: {UserID: 1, ID: 11, Foo: "foo val 2"},
+				12: {UserID: 2, ID: 12, Foo: "foo val 3"},
+			},
+		},
+		{
+			name: "key column is also scanned into the struct field",
+			dst:  &map[int64]orderWithPK{},
+			opts: []dbscan.ScanOption{dbscan.WithKeyColumn("user_id")},
+			expected: &map[int64]orderWithPK{
+				1: {UserID: 1, ID: 11, Foo: "foo val 2"},
+				2: {UserID: 2, ID: 12, Foo: "foo val 3"},
+			},
+		},
+		{
+			name: "maps keep the key column",
+			dst:  &map[int64]map[string]interface{}{},
+			opts: []dbscan.ScanOption{dbscan.WithKeyColumn("id")},
+			expected: &map[int64]map[string]interface{}{
+				10: {"user_id": int64(1), "id": int64(10), "foo": "foo val"},
+				11: {"user_id": int64(1), "id": int64(11), "foo": "foo val 2"},
+				12: {"user_id": int64(2), "id": int64(12), "foo": "foo val 3"},
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rows := queryRows(t, keyedRowsQuery)
+			err := testAPI.ScanAll(tc.dst, rows, tc.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, tc.dst)
+		})
+	}
+}
+
+func TestScanAll_keyedMap_primitiveValues(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT *
+		FROM (
+			VALUES (1::INT8, 'foo val'), (1, 'foo val 2'), (2, 'foo val 3')
+		) AS t (user_id, foo)
+	`
+	var got map[int64][]string
+	err := testAPI.ScanAll(&got, queryRows(t, query), dbscan.WithKeyColumn("user_id"))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[int64][]string{1: {"foo val", "foo val 2"}, 2: {"foo val 3"}}, got)
+}
+
+func TestScanAll_keyedSet(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT *
+		FROM (
+			VALUES ('foo val'), ('foo val 2'), ('foo val')
+		) AS t (foo)
+	`
+	got := map[string]struct{}{"stale": {}}
+	err := testAPI.ScanAll(&got, queryRows(t, query))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]struct{}{"foo val": {}, "foo val 2": {}}, got)
+}
+
+func TestScanAll_keyedMap_returnsErr(t *testing.T) {
+	t.Parallel()
+	type order struct {
+		ID  int64
+		Foo string
+	}
+	type twoPKs struct {
+		UserID int64 `db:",pk"`
+		ID     int64 `db:",pk"`
+		Foo    string
+	}
+	cases := []struct {
+		name        string
+		dst         interface{}
+		opts        []dbscan.ScanOption
+		expectedErr string
+	}{
+		{
+			name: "key isn't specified",
+			dst:  &map[int64]order{},
+			expectedErr: "parsing map destination: scany: map key isn't specified, " +
+				`use WithKeyColumn scan option or "pk" tag option`,
+		},
+		{
+			name:        "key column is absent",
+			dst:         &map[int64]order{},
+			opts:        []dbscan.ScanOption{dbscan.WithKeyColumn("bar")},
+			expectedErr: "parsing map destination: scany: key column 'bar' isn't found in rows",
+		},
+		{
+			name: "more than one pk field",
+			dst:  &map[int64]twoPKs{},
+			expectedErr: "parsing map destination: scany: dbscan_test.twoPKs has more than one field " +
+				`with "pk" tag option`,
+		},
+		{
+			name:        "duplicate key",
+			dst:         &map[int64]order{},
+			opts:        []dbscan.ScanOption{dbscan.WithKeyColumn("user_id"), dbscan.WithUniqueKeys(true)},
+			expectedErr: "scanning: scany: duplicate map key: 1",
+		},
+		{
+			name: "unique keys of grouped map",
+			dst:  &map[int64][]order{},
+			opts: []dbscan.ScanOption{dbscan.WithKeyColumn("user_id"), dbscan.WithUniqueKeys(true)},
+			expectedErr: "parsing map destination: scany: WithUniqueKeys scan option can't be used with " +
+				"map[int64][]dbscan_test.order destination, it collects all rows with the same key",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rows := queryRows(t, keyedRowsQuery)
+			err := testAPI.ScanAll(tc.dst, rows, tc.opts...)
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
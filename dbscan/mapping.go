@@ -0,0 +1,121 @@
+package dbscan
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RegisterMapping maps fields of a struct type to columns without struct tags on the type.
+// It's useful for types from other packages that can't be changed, like generated or vendor SDK types.
+//
+// example is a value of the struct type or a pointer to it.
+// mapping keys are field paths relative to the struct type, nested fields are separated by ".",
+// for example "Address.City". Embedded structs are part of the path as well.
+// mapping values have the same format as the struct tag value: the column name, optionally followed by options,
+// or "-" to ignore the field. Fields that aren't listed in the mapping are handled as usual.
+//
+// The mapping is applied wherever the type is scanned, both as a destination and as a nested struct.
+// If mappings of several types cover the same field, the mapping of the outer type wins.
+// It's safe to call RegisterMapping concurrently with scanning,
+// but a type can be registered only once.
+func (api *API) RegisterMapping(example interface{}, mapping map[string]string) error {
+	structType := reflect.TypeOf(example)
+	if structType != nil && structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return fmt.Errorf("scany: mapping can be registered only for a struct type, got %T", example)
+	}
+	copied := make(map[string]string, len(mapping))
+	for path, tag := range mapping {
+		if err := validateFieldPath(structType, path); err != nil {
+			return fmt.Errorf("scany: register mapping for %v: %w", structType, err)
+		}
+		copied[path] = tag
+	}
+
+	api.mappingsMu.Lock()
+	defer api.mappingsMu.Unlock()
+	if _, ok := api.mappings[structType]; ok {
+		return fmt.Errorf("scany: mapping for %v is registered more than once", structType)
+	}
+	if api.mappings == nil {
+		api.mappings = make(map[reflect.Type]map[string]string)
+	}
+	api.mappings[structType] = copied
+	return nil
+}
+
+// validateFieldPath checks that the path consists of exported or embedded fields of nested structs.
+func validateFieldPath(structType reflect.Type, path string) error {
+	t := structType
+	parts := strings.Split(path, ".")
+	for i, name := range parts {
+		if i > 0 {
+			if isNullType(t) {
+				t = t.Field(nullValueFieldIndex).Type
+			}
+			if t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t.Kind() != reflect.Struct {
+				return fmt.Errorf("field %q: %s isn't a struct", path, strings.Join(parts[:i], "."))
+			}
+		}
+		field, ok := directField(t, name)
+		if !ok {
+			return fmt.Errorf("field %q: no such field", path)
+		}
+		if field.PkgPath != "" && !field.Anonymous {
+			return fmt.Errorf("field %q: field is unexported", path)
+		}
+		t = field.Type
+	}
+	return nil
+}
+
+// directField is the same as reflect.Type.FieldByName, but it doesn't look for promoted fields.
+func directField(structType reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < structType.NumField(); i++ {
+		if field := structType.Field(i); field.Name == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// activeMapping is a registered mapping that applies to the struct being traversed.
+type activeMapping struct {
+	mapping map[string]string
+	// path is the field path from the registered type to the struct being traversed, ends with ".".
+	path string
+}
+
+// lookupMapping returns the mapping value for the field, if any of the active mappings covers it.
+func lookupMapping(mappings []*activeMapping, fieldName string) (string, bool) {
+	for _, m := range mappings {
+		if tag, ok := m.mapping[m.path+fieldName]; ok {
+			return tag, true
+		}
+	}
+	return "", false
+}
+
+// nestedMappings returns mappings that apply to the struct type nested into the field.
+func (api *API) nestedMappings(mappings []*activeMapping, fieldName string, childType reflect.Type) []*activeMapping {
+	result := make([]*activeMapping, 0, len(mappings)+1)
+	for _, m := range mappings {
+		result = append(result, &activeMapping{mapping: m.mapping, path: m.path + fieldName + "."})
+	}
+	if mapping := api.getMapping(childType); mapping != nil {
+		result = append(result, &activeMapping{mapping: mapping})
+	}
+	return result
+}
+
+func (api *API) getMapping(structType reflect.Type) map[string]string {
+	api.mappingsMu.RLock()
+	defer api.mappingsMu.RUnlock()
+	return api.mappings[structType]
+}
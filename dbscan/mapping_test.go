@@ -0,0 +1,109 @@
+package dbscan_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type vendorAddress struct {
+	Street string
+	Zip    string
+}
+
+type vendorUser struct {
+	UserID  string
+	Name    string
+	Secret  string
+	Address vendorAddress
+}
+
+func TestAPI_RegisterMapping(t *testing.T) {
+	t.Parallel()
+	api, err := getAPI()
+	require.NoError(t, err)
+	err = api.RegisterMapping(vendorUser{}, map[string]string{
+		"UserID":         "id",
+		"Secret":         "-",
+		"Address":        "addr",
+		"Address.Street": "line1",
+	})
+	require.NoError(t, err)
+	err = api.RegisterMapping(&vendorAddress{}, map[string]string{
+		"Zip": "zip_code,default=00000",
+	})
+	require.NoError(t, err)
+	query := `
+		SELECT 'id val' AS id, 'name val' AS name, 'street val' AS "addr.line1"
+	`
+	rows := queryRows(t, query)
+	defer rows.Close() //nolint: errcheck
+	rows.Next()
+
+	dst := &vendorUser{}
+	err = api.ScanRow(dst, rows)
+	require.NoError(t, err)
+	requireNoRowsErrorsAndClose(t, rows)
+
+	expected := &vendorUser{
+		UserID:  "id val",
+		Name:    "name val",
+		Address: vendorAddress{Street: "street val", Zip: "00000"},
+	}
+	assert.Equal(t, expected, dst)
+}
+
+func TestAPI_RegisterMapping_InvalidInput(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name        string
+		example     interface{}
+		mapping     map[string]string
+		expectedErr string
+	}{
+		{
+			name:        "not a struct",
+			example:     1,
+			expectedErr: "scany: mapping can be registered only for a struct type, got int",
+		},
+		{
+			name:        "unknown field",
+			example:     vendorUser{},
+			mapping:     map[string]string{"Address.Foo": "foo"},
+			expectedErr: "scany: register mapping for dbscan_test.vendorUser: field \"Address.Foo\": no such field",
+		},
+		{
+			name:    "path through non-struct field",
+			example: vendorUser{},
+			mapping: map[string]string{"Name.Foo": "foo"},
+			expectedErr: "scany: register mapping for dbscan_test.vendorUser: " +
+				"field \"Name.Foo\": Name isn't a struct",
+		},
+		{
+			name:        "unexported field",
+			example:     struct{ foo string }{},
+			mapping:     map[string]string{"foo": "foo"},
+			expectedErr: "scany: register mapping for struct { foo string }: field \"foo\": field is unexported",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			api, err := getAPI()
+			require.NoError(t, err)
+			err = api.RegisterMapping(tc.example, tc.mapping)
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestAPI_RegisterMapping_registeredTwice_returnsErr(t *testing.T) {
+	t.Parallel()
+	api, err := getAPI()
+	require.NoError(t, err)
+	require.NoError(t, api.RegisterMapping(vendorUser{}, map[string]string{"UserID": "id"}))
+	err = api.RegisterMapping(&vendorUser{}, map[string]string{"UserID": "user_id"})
+	assert.EqualError(t, err, "scany: mapping for dbscan_test.vendorUser is registered more than once")
+}
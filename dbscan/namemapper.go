@@ -0,0 +1,158 @@
+package dbscan
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	snakeCaseMapper      = NewSnakeCaseMapper()
+	lowerCamelCaseMapper = newCachedMapper(func(name string) string {
+		words := splitWords(name, nil)
+		for i, word := range words {
+			word = strings.ToLower(word)
+			if i > 0 && isLower(word[0]) {
+				word = string(word[0]-'a'+'A') + word[1:]
+			}
+			words[i] = word
+		}
+		return strings.Join(words, "")
+	})
+	screamingSnakeCaseMapper = newCachedMapper(func(name string) string {
+		return strings.ToUpper(strings.Join(splitWords(name, nil), "_"))
+	})
+	kebabCaseMapper = newCachedMapper(func(name string) string {
+		return strings.ToLower(strings.Join(splitWords(name, nil), "-"))
+	})
+)
+
+// SnakeCaseMapper is a NameMapperFunc that maps struct field to snake case,
+// for example, "HTTPServerURL" is mapped to "http_server_url".
+// Use NewSnakeCaseMapper for acronyms that contain lowercase letters or digits, like "IPv6".
+func SnakeCaseMapper(str string) string {
+	return snakeCaseMapper(str)
+}
+
+// LowerCamelCaseMapper is a NameMapperFunc that maps struct field to lower camel case,
+// for example, "HTTPServerURL" is mapped to "httpServerUrl".
+func LowerCamelCaseMapper(str string) string {
+	return lowerCamelCaseMapper(str)
+}
+
+// LowercaseMapper is a NameMapperFunc that maps struct field to lowercase without separating words,
+// for example, "HTTPServerURL" is mapped to "httpserverurl".
+func LowercaseMapper(str string) string {
+	return strings.ToLower(str)
+}
+
+// ScreamingSnakeCaseMapper is a NameMapperFunc that maps struct field to upper snake case,
+// for example, "HTTPServerURL" is mapped to "HTTP_SERVER_URL".
+func ScreamingSnakeCaseMapper(str string) string {
+	return screamingSnakeCaseMapper(str)
+}
+
+// KebabCaseMapper is a NameMapperFunc that maps struct field to kebab case,
+// for example, "HTTPServerURL" is mapped to "http-server-url".
+func KebabCaseMapper(str string) string {
+	return kebabCaseMapper(str)
+}
+
+// NewSnakeCaseMapper returns a NameMapperFunc that maps struct field to snake case
+// and keeps the provided acronyms as single words, for example,
+// with "IPv6" acronym "RemoteIPv6Addr" is mapped to "remote_ipv6_addr" instead of "remote_i_pv6_addr".
+// Acronyms are case-sensitive and can be followed by the plural "s", like "IDs".
+// Acronyms that consist of uppercase letters only, like "HTTP", are recognized without the dictionary.
+func NewSnakeCaseMapper(acronyms ...string) NameMapperFunc {
+	sorted := make([]string, 0, len(acronyms))
+	for _, acronym := range acronyms {
+		if acronym != "" {
+			sorted = append(sorted, acronym)
+		}
+	}
+	// Longer acronyms go first, so "IPv6" wins over "IP".
+	sort.SliceStable(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	return newCachedMapper(func(name string) string {
+		return strings.ToLower(strings.Join(splitWords(name, sorted), "_"))
+	})
+}
+
+// newCachedMapper wraps the mapper with a cache by field name.
+// The number of distinct field names is limited by the program types, so the cache doesn't need eviction.
+func newCachedMapper(mapper func(string) string) NameMapperFunc {
+	var cache sync.Map
+	return func(name string) string {
+		if column, ok := cache.Load(name); ok {
+			return column.(string)
+		}
+		column := mapper(name)
+		cache.Store(name, column)
+		return column
+	}
+}
+
+// splitWords splits the field name into words.
+// A new word starts with an uppercase letter that either follows a lowercase letter or a digit,
+// or is followed by a lowercase letter, so "HTTPServer" is split into "HTTP" and "Server".
+// Acronyms are always separate words.
+func splitWords(name string, acronyms []string) []string {
+	var words []string
+	start := 0
+	acronymEnd := -1
+	for i := 0; i < len(name); {
+		if end, ok := matchAcronym(name, i, acronyms, i == acronymEnd); ok {
+			if start < i {
+				words = append(words, name[start:i])
+			}
+			words = append(words, name[i:end])
+			start, i, acronymEnd = end, end, end
+			continue
+		}
+		if i > start && isWordStart(name, i) {
+			words = append(words, name[start:i])
+			start = i
+		}
+		i++
+	}
+	if start < len(name) {
+		words = append(words, name[start:])
+	}
+	return words
+}
+
+func isWordStart(name string, i int) bool {
+	if !isUpper(name[i]) {
+		return false
+	}
+	if i+1 < len(name) && isLower(name[i+1]) {
+		return true
+	}
+	prev := name[i-1]
+	return isLower(prev) || isDigit(prev)
+}
+
+// matchAcronym returns the end of the acronym that starts at i.
+// An acronym must start a word, so it isn't matched in the middle of an uppercase sequence,
+// unless the previous acronym ends there, and it must not be followed by a lowercase letter except the plural "s".
+func matchAcronym(name string, i int, acronyms []string, afterAcronym bool) (int, bool) {
+	if i > 0 && !afterAcronym && isUpper(name[i-1]) {
+		return 0, false
+	}
+	for _, acronym := range acronyms {
+		if !strings.HasPrefix(name[i:], acronym) {
+			continue
+		}
+		end := i + len(acronym)
+		if end < len(name) && name[end] == 's' && (end+1 == len(name) || !isLower(name[end+1])) {
+			end++
+		}
+		if end == len(name) || !isLower(name[end]) {
+			return end, true
+		}
+	}
+	return 0, false
+}
+
+func isUpper(c byte) bool { return c >= 'A' && c <= 'Z' }
+func isLower(c byte) bool { return c >= 'a' && c <= 'z' }
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
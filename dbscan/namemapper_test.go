@@ -0,0 +1,52 @@
+package dbscan_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/georgysavva/scany/v2/dbscan"
+)
+
+func TestNameMappers(t *testing.T) {
+	t.Parallel()
+	snakeCaseWithAcronyms := dbscan.NewSnakeCaseMapper("IPv6", "ID", "OAuth")
+	cases := []struct {
+		name     string
+		mapper   dbscan.NameMapperFunc
+		input    string
+		expected string
+	}{
+		{name: "snake case", mapper: dbscan.SnakeCaseMapper, input: "FirstName", expected: "first_name"},
+		{name: "snake case with acronyms", mapper: dbscan.SnakeCaseMapper, input: "HTTPServerURL", expected: "http_server_url"},
+		{name: "snake case with digits", mapper: dbscan.SnakeCaseMapper, input: "Address2Line", expected: "address2_line"},
+		{name: "snake case single word", mapper: dbscan.SnakeCaseMapper, input: "ID", expected: "id"},
+		{
+			name:     "snake case with acronyms dictionary",
+			mapper:   snakeCaseWithAcronyms,
+			input:    "RemoteIPv6Addr",
+			expected: "remote_ipv6_addr",
+		},
+		{name: "snake case with plural acronym", mapper: snakeCaseWithAcronyms, input: "UserIDs", expected: "user_ids"},
+		{name: "snake case with leading acronym", mapper: snakeCaseWithAcronyms, input: "OAuthToken", expected: "oauth_token"},
+		{name: "snake case ignores acronym prefix", mapper: snakeCaseWithAcronyms, input: "Identity", expected: "identity"},
+		{name: "lower camel case", mapper: dbscan.LowerCamelCaseMapper, input: "HTTPServerURL", expected: "httpServerUrl"},
+		{name: "lowercase", mapper: dbscan.LowercaseMapper, input: "HTTPServerURL", expected: "httpserverurl"},
+		{
+			name:     "screaming snake case",
+			mapper:   dbscan.ScreamingSnakeCaseMapper,
+			input:    "HTTPServerURL",
+			expected: "HTTP_SERVER_URL",
+		},
+		{name: "kebab case", mapper: dbscan.KebabCaseMapper, input: "HTTPServerURL", expected: "http-server-url"},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, tc.mapper(tc.input))
+			// The second call is served from the cache.
+			assert.Equal(t, tc.expected, tc.mapper(tc.input))
+		})
+	}
+}
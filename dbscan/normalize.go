@@ -0,0 +1,125 @@
+package dbscan
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// WithTimeLocation makes dbscan convert all scanned time.Time values into the provided location,
+// that includes time values in nested structs, pointers, slices and Null.
+// Use time.UTC to normalize all time values to UTC.
+// Zero time values are left as is.
+func WithTimeLocation(loc *time.Location) APIOption {
+	return func(api *API) {
+		api.timeLocation = loc
+	}
+}
+
+// WithTrimCharPadding makes dbscan strip trailing spaces from string values of CHAR(n) columns.
+// Databases pad CHAR(n) column values with spaces up to n characters,
+// this option allows to get the original string value.
+// Column types are detected via ColumnTypesRows interface, values of other columns, like VARCHAR or TEXT,
+// are left as is. If rows don't report column types, no values are trimmed.
+func WithTrimCharPadding(trim bool) APIOption {
+	return func(api *API) {
+		api.trimCharPadding = trim
+	}
+}
+
+// WithEmptyStringAsNil makes dbscan set pointer to string fields to nil if the scanned string is empty.
+// It's applied after WithTrimCharPadding, so a string that consists of spaces only also becomes nil.
+func WithEmptyStringAsNil(enabled bool) APIOption {
+	return func(api *API) {
+		api.emptyStringAsNil = enabled
+	}
+}
+
+func (api *API) normalizationEnabled() bool {
+	return api.timeLocation != nil || api.trimCharPadding || api.emptyStringAsNil
+}
+
+// paddedColumns reports for every column of the rows whether its values are padded with spaces,
+// it's nil if WithTrimCharPadding is disabled or rows don't report column types.
+func (api *API) paddedColumns(rows Rows) ([]bool, error) {
+	if !api.trimCharPadding {
+		return nil, nil
+	}
+	ctRows, ok := rows.(ColumnTypesRows)
+	if !ok {
+		return nil, nil
+	}
+	columnTypes, err := ctRows.ColumnTypes()
+	if err != nil {
+		return nil, fmt.Errorf("scany: get rows column types: %w", err)
+	}
+	padded := make([]bool, len(columnTypes))
+	for i, ct := range columnTypes {
+		padded[i] = isPaddedCharType(ct.DatabaseTypeName)
+	}
+	return padded, nil
+}
+
+// isPaddedCharType reports whether the database type is a fixed length character type, like CHAR(n),
+// or an array of it.
+func isPaddedCharType(databaseTypeName string) bool {
+	name := strings.ToUpper(databaseTypeName)
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = name[:i]
+	}
+	name = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(name), "_"), "[]")
+	switch name {
+	case "CHAR", "BPCHAR", "CHARACTER", "NCHAR":
+		return true
+	}
+	return false
+}
+
+// isPaddedColumn reports whether the column at the position is padded, see paddedColumns.
+func isPaddedColumn(padded []bool, position int) bool {
+	return position >= 0 && position < len(padded) && padded[position]
+}
+
+// normalizeValue post-processes the scanned value according to the normalization options.
+// trimPadding reports whether the value comes from a padded column, see WithTrimCharPadding.
+func (api *API) normalizeValue(v reflect.Value, trimPadding bool) {
+	valueType := v.Type()
+	switch {
+	case valueType == timeType:
+		t := v.Interface().(time.Time)
+		if api.timeLocation != nil && !t.IsZero() {
+			v.Set(reflect.ValueOf(t.In(api.timeLocation)))
+		}
+	case isNullType(valueType):
+		if v.Field(nullValidFieldIndex).Bool() {
+			api.normalizeValue(v.Field(nullValueFieldIndex), trimPadding)
+		}
+	case v.Kind() == reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		elem := v.Elem()
+		api.normalizeValue(elem, trimPadding)
+		if api.emptyStringAsNil && elem.Kind() == reflect.String && elem.Len() == 0 {
+			v.Set(reflect.Zero(valueType))
+		}
+	case v.Kind() == reflect.String:
+		if trimPadding {
+			v.SetString(strings.TrimRight(v.String(), " "))
+		}
+	case v.Kind() == reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		// Values stored in an interface aren't addressable, so we normalize a copy.
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		api.normalizeValue(elem, trimPadding)
+		v.Set(elem)
+	case v.Kind() == reflect.Slice && valueType.Elem().Kind() != reflect.Uint8:
+		for i := 0; i < v.Len(); i++ {
+			api.normalizeValue(v.Index(i), trimPadding)
+		}
+	}
+}
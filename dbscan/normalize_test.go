@@ -0,0 +1,110 @@
+package dbscan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/georgysavva/scany/v2/dbscan"
+)
+
+func TestRowScanner_Scan_normalizationOptions(t *testing.T) {
+	t.Parallel()
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	ts := time.Date(2020, 10, 16, 9, 36, 59, 0, time.UTC).In(loc)
+	cases := []struct {
+		name     string
+		opts     []dbscan.APIOption
+		query    string
+		expected interface{}
+	}{
+		{
+			name: "time fields are converted into location",
+			opts: []dbscan.APIOption{dbscan.WithTimeLocation(loc)},
+			query: `
+				SELECT '2020-10-16 09:36:59+00:00'::timestamptz AS foo,
+					'2020-10-16 09:36:59+00:00'::timestamptz AS bar,
+					'2020-10-16 09:36:59+00:00'::timestamptz AS "nested.baz",
+					NULL::timestamptz AS qux
+			`,
+			expected: struct {
+				Foo    time.Time
+				Bar    *time.Time
+				Nested struct {
+					Baz dbscan.Null[time.Time]
+				}
+				Qux *time.Time
+			}{
+				Foo: ts,
+				Bar: &ts,
+				Nested: struct {
+					Baz dbscan.Null[time.Time]
+				}{Baz: dbscan.NewNull(ts)},
+				Qux: nil,
+			},
+		},
+		{
+			name: "char padding is trimmed",
+			opts: []dbscan.APIOption{dbscan.WithTrimCharPadding(true)},
+			query: `
+				SELECT 'foo'::CHAR(5) AS foo, 'bar'::CHAR(5) AS bar
+			`,
+			expected: struct {
+				Foo string
+				Bar *string
+			}{
+				Foo: "foo",
+				Bar: makeStrPtr("bar"),
+			},
+		},
+		{
+			name: "text values keep trailing spaces",
+			opts: []dbscan.APIOption{dbscan.WithTrimCharPadding(true)},
+			query: `
+				SELECT 'foo  '::TEXT AS foo, 'bar '::VARCHAR AS bar, 'baz'::CHAR(5) AS baz
+			`,
+			expected: struct {
+				Foo string
+				Bar *string
+				Baz string
+			}{
+				Foo: "foo  ",
+				Bar: makeStrPtr("bar "),
+				Baz: "baz",
+			},
+		},
+		{
+			name: "empty strings become nil",
+			opts: []dbscan.APIOption{dbscan.WithTrimCharPadding(true), dbscan.WithEmptyStringAsNil(true)},
+			query: `
+				SELECT '' AS foo, ' '::CHAR(3) AS bar, '' AS baz
+			`,
+			expected: struct {
+				Foo *string
+				Bar *string
+				Baz string
+			}{
+				Foo: nil,
+				Bar: nil,
+				Baz: "",
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			api, err := getAPI(tc.opts...)
+			require.NoError(t, err)
+			rows := queryRows(t, tc.query)
+			defer rows.Close() //nolint: errcheck
+			dst := allocateDestination(tc.expected)
+			rows.Next()
+			err = api.ScanRow(dst, rows)
+			require.NoError(t, err)
+			requireNoRowsErrorsAndClose(t, rows)
+			assertDestinationEqual(t, tc.expected, dst)
+		})
+	}
+}
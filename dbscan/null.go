@@ -0,0 +1,159 @@
+package dbscan
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Null represents a value of type T that may be NULL.
+// Valid is true if V holds a value, and false if the value is NULL.
+//
+// Null implements sql.Scanner and driver.Valuer, so it can be used with database/sql and pgx directly,
+// as well as json.Marshaler, json.Unmarshaler, encoding.TextMarshaler and encoding.TextUnmarshaler.
+// For pgx, pgxscan.RegisterNullScanPlan lets the column codec decode T natively, arrays included.
+//
+// dbscan recognises Null natively. Instead of passing it to the database library as is,
+// dbscan scans the column into *T and sets Valid depending on whether the value was NULL,
+// so T can be any type that the database library is able to scan into.
+// If T is a struct, dbscan also maps its fields to the columns prefixed with the Null field name,
+// the same way as for a regular nested struct, for example:
+//
+//	type User struct {
+//	    ID      string
+//	    Address dbscan.Null[Address]
+//	}
+//
+//	type Address struct {
+//	    City   string
+//	    Street string
+//	}
+//
+// User struct is mapped to the following columns: "id", "address.city", "address.street".
+// If all "address.*" columns are NULL, User.Address.Valid is false, otherwise it's true.
+type Null[T any] struct {
+	V     T
+	Valid bool
+}
+
+// Indexes of the Null struct fields.
+const (
+	nullValueFieldIndex = 0
+	nullValidFieldIndex = 1
+)
+
+type nullType interface {
+	isNull()
+}
+
+var nullTypeInterface = reflect.TypeOf((*nullType)(nil)).Elem()
+
+func (n Null[T]) isNull() {}
+
+// IsNullType reports whether t is Null[T] for some T.
+// It's meant for database library adapters that scan Null values natively, like pgxscan.
+func IsNullType(t reflect.Type) bool {
+	return isNullType(t)
+}
+
+// isNullType returns true if t is Null[T] for some T.
+func isNullType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.Implements(nullTypeInterface)
+}
+
+// NewNull returns a valid Null holding v.
+func NewNull[T any](v T) Null[T] {
+	return Null[T]{V: v, Valid: true}
+}
+
+// Ptr returns a pointer to the value or nil if it's NULL.
+func (n Null[T]) Ptr() *T {
+	if !n.Valid {
+		return nil
+	}
+	v := n.V
+	return &v
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *Null[T]) Scan(src interface{}) error {
+	if src == nil {
+		var zero T
+		n.V, n.Valid = zero, false
+		return nil
+	}
+	if err := assignValue(reflect.ValueOf(&n.V).Elem(), src); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (n Null[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	if valuer, ok := interface{}(n.V).(driver.Valuer); ok {
+		return valuer.Value()
+	}
+	return driver.DefaultParameterConverter.ConvertValue(n.V)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.V)
+}
+
+var jsonNull = []byte("null")
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (n *Null[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, jsonNull) {
+		var zero T
+		n.V, n.Valid = zero, false
+		return nil
+	}
+	if err := json.Unmarshal(data, &n.V); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// NULL is represented as an empty text.
+func (n Null[T]) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+	if marshaler, ok := interface{}(n.V).(encoding.TextMarshaler); ok {
+		return marshaler.MarshalText()
+	}
+	v := reflect.ValueOf(n.V)
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+		return v.Bytes(), nil
+	}
+	return []byte(fmt.Sprint(n.V)), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// An empty text is treated as NULL.
+func (n *Null[T]) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		var zero T
+		n.V, n.Valid = zero, false
+		return nil
+	}
+	if err := assignString(reflect.ValueOf(&n.V).Elem(), string(text)); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
@@ -0,0 +1,211 @@
+package dbscan_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/georgysavva/scany/v2/dbscan"
+)
+
+type NullNested struct {
+	Foo string
+	Bar *string
+}
+
+func TestRowScanner_Scan_nullDestination(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		query    string
+		expected interface{}
+	}{
+		{
+			name: "primitive Null fields",
+			query: `
+				SELECT 'foo val' AS foo, NULL AS bar, 5 AS baz
+			`,
+			expected: struct {
+				Foo dbscan.Null[string]
+				Bar dbscan.Null[string]
+				Baz dbscan.Null[int]
+			}{
+				Foo: dbscan.NewNull("foo val"),
+				Bar: dbscan.Null[string]{},
+				Baz: dbscan.NewNull(5),
+			},
+		},
+		{
+			name: "Null time field",
+			query: `
+				SELECT '2020-10-16 09:36:59+00:00'::timestamp AS foo
+			`,
+			expected: struct {
+				Foo dbscan.Null[time.Time]
+			}{
+				Foo: dbscan.NewNull(time.Date(2020, 10, 16, 9, 36, 59, 0, time.UTC)),
+			},
+		},
+		{
+			name: "Null struct field is filled from prefixed columns",
+			query: `
+				SELECT 'foo val' AS "nested.foo", NULL AS "nested.bar"
+			`,
+			expected: struct {
+				Nested dbscan.Null[NullNested]
+			}{
+				Nested: dbscan.NewNull(NullNested{Foo: "foo val"}),
+			},
+		},
+		{
+			name: "Null struct field is invalid if all prefixed columns are NULL",
+			query: `
+				SELECT NULL AS "nested.foo", NULL AS "nested.bar"
+			`,
+			expected: struct {
+				Nested dbscan.Null[NullNested]
+			}{
+				Nested: dbscan.Null[NullNested]{},
+			},
+		},
+		{
+			name: "Null struct field is filled from a json column",
+			query: `
+				SELECT '{"key": "key val"}'::JSON AS foo_json, NULL::JSON AS bar_json
+			`,
+			expected: struct {
+				FooJSON dbscan.Null[JSONObj]
+				BarJSON dbscan.Null[JSONObj]
+			}{
+				FooJSON: dbscan.NewNull(JSONObj{Key: "key val"}),
+				BarJSON: dbscan.Null[JSONObj]{},
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rows := queryRows(t, tc.query)
+			dst := allocateDestination(tc.expected)
+			err := scan(t, dst, rows)
+			require.NoError(t, err)
+			assertDestinationEqual(t, tc.expected, dst)
+		})
+	}
+}
+
+func TestScanAll_nullElements(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT *
+		FROM (
+			VALUES ('foo val'), (NULL), ('foo val 3')
+		) AS t (foo)
+	`
+	rows := queryRows(t, query)
+	expected := []dbscan.Null[string]{dbscan.NewNull("foo val"), {}, dbscan.NewNull("foo val 3")}
+
+	var got []dbscan.Null[string]
+	err := testAPI.ScanAll(&got, rows)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
+
+func TestIsNullType(t *testing.T) {
+	t.Parallel()
+	type aliasNull = dbscan.Null[string]
+	assert.True(t, dbscan.IsNullType(reflect.TypeOf(dbscan.Null[int]{})))
+	assert.True(t, dbscan.IsNullType(reflect.TypeOf(aliasNull{})))
+	assert.True(t, dbscan.IsNullType(reflect.TypeOf(dbscan.Null[NullNested]{})))
+	assert.False(t, dbscan.IsNullType(reflect.TypeOf(sql.NullString{})))
+	assert.False(t, dbscan.IsNullType(reflect.TypeOf(&dbscan.Null[int]{})))
+}
+
+func TestNull_Scan(t *testing.T) {
+	t.Parallel()
+	var n dbscan.Null[int]
+
+	require.NoError(t, n.Scan(int64(12)))
+	assert.Equal(t, dbscan.NewNull(12), n)
+
+	require.NoError(t, n.Scan([]byte("13")))
+	assert.Equal(t, dbscan.NewNull(13), n)
+
+	require.NoError(t, n.Scan(nil))
+	assert.Equal(t, dbscan.Null[int]{}, n)
+
+	assert.EqualError(t, n.Scan(1.5), "scany: converting 1.5 to int: value out of range")
+}
+
+func TestNull_Scan_numberOutOfRange_returnsErr(t *testing.T) {
+	t.Parallel()
+	var u dbscan.Null[uint64]
+	assert.EqualError(t, u.Scan(int64(-1)), "scany: converting -1 to uint64: value out of range")
+	assert.EqualError(t, u.Scan(float64(-2)), "scany: converting -2 to uint64: value out of range")
+
+	var i8 dbscan.Null[int8]
+	assert.EqualError(t, i8.Scan(int64(300)), "scany: converting 300 to int8: value out of range")
+	require.NoError(t, i8.Scan(int64(-128)))
+	assert.Equal(t, dbscan.NewNull(int8(-128)), i8)
+
+	var i64 dbscan.Null[int64]
+	assert.EqualError(t, i64.Scan(uint64(1<<63)), "scany: converting 9223372036854775808 to int64: value out of range")
+
+	var f32 dbscan.Null[float32]
+	assert.EqualError(t, f32.Scan(1e300), "scany: converting 1e+300 to float32: value out of range")
+	assert.EqualError(t, f32.Scan(int64(1<<25+1)), "scany: converting 33554433 to float32: value out of range")
+	require.NoError(t, f32.Scan(0.5))
+	assert.Equal(t, dbscan.NewNull(float32(0.5)), f32)
+}
+
+func TestNull_Value(t *testing.T) {
+	t.Parallel()
+	v, err := dbscan.NewNull(int32(12)).Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), v)
+
+	v, err = dbscan.Null[string]{}.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestNull_JSON(t *testing.T) {
+	t.Parallel()
+	type model struct {
+		Foo dbscan.Null[string] `json:"foo"`
+		Bar dbscan.Null[int]    `json:"bar"`
+	}
+	data, err := json.Marshal(model{Foo: dbscan.NewNull("foo val")})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"foo": "foo val", "bar": null}`, string(data))
+
+	var got model
+	err = json.Unmarshal([]byte(`{"foo": null, "bar": 5}`), &got)
+	require.NoError(t, err)
+	assert.Equal(t, model{Bar: dbscan.NewNull(5)}, got)
+}
+
+func TestNull_Text(t *testing.T) {
+	t.Parallel()
+	text, err := dbscan.NewNull(time.Date(2020, 10, 16, 9, 36, 59, 0, time.UTC)).MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "2020-10-16T09:36:59Z", string(text))
+
+	text, err = dbscan.Null[int]{}.MarshalText()
+	require.NoError(t, err)
+	assert.Empty(t, text)
+
+	var n dbscan.Null[time.Duration]
+	require.NoError(t, n.UnmarshalText([]byte("5s")))
+	assert.Equal(t, dbscan.NewNull(5*time.Second), n)
+
+	require.NoError(t, n.UnmarshalText(nil))
+	assert.Equal(t, dbscan.Null[time.Duration]{}, n)
+}
@@ -0,0 +1,285 @@
+package dbscan
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ScanPivot is a package-level helper function that uses the DefaultAPI object.
+// See API.ScanPivot for details.
+func ScanPivot(dst interface{}, rows Rows, entityColumn, keyColumn, valueColumn string) error {
+	return DefaultAPI.ScanPivot(dst, rows, entityColumn, keyColumn, valueColumn)
+}
+
+// ScanPivot scans entity-attribute-value rows, like (entity_id, key, value), into a slice of structs.
+// Rows are grouped by the entity column, the destination gets one struct per entity
+// in the order of the first row of every entity, rows of the same entity don't have to be adjacent.
+// The value column of every row is scanned into the struct field that the row key is mapped to,
+// the same way as a column with the key name would be scanned by ScanAll.
+// Values are converted into the field types, so a textual value column can fill numeric or time fields,
+// NULL resets the field to zero unless the field has a default value.
+// A Null struct is valid if any row of the entity has a non NULL value for a field inside it.
+// The entity column is scanned into the struct field mapped to it, if there is one.
+//
+// Keys without a corresponding field go to the field with the "remain" tag option,
+// otherwise ScanPivot returns an error, unless WithAllowUnknownColumns option is enabled.
+// Fields without rows for an entity get their default values, see TagOptionDefault.
+// The destination must be a pointer to a slice of structs or pointers to structs,
+// ScanPivot resets the slice before starting.
+func (api *API) ScanPivot(dst interface{}, rows Rows, entityColumn, keyColumn, valueColumn string) error {
+	defer rows.Close() //nolint: errcheck
+	sliceMeta, err := api.parseSliceDestination(dst)
+	if err != nil {
+		return fmt.Errorf("parsing slice destination: %w", err)
+	}
+	if !api.isStructValue(sliceMeta.elementBaseType) {
+		return fmt.Errorf("scany: pivot destination element must be a struct, got: %v", sliceMeta.elementBaseType)
+	}
+	// Make sure slice is empty.
+	sliceMeta.val.Set(sliceMeta.val.Slice(0, 0))
+	ps, err := api.newPivotScanner(rows, sliceMeta.elementBaseType, entityColumn, keyColumn, valueColumn)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		if err := ps.scanRow(); err != nil {
+			return fmt.Errorf("scanning: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("scany: rows final error: %w", err)
+	}
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("scany: close rows after processing: %w", err)
+	}
+	for i, element := range ps.elements {
+		if err := ps.runAfterScanHooks(i); err != nil {
+			return fmt.Errorf("after scan: %w", err)
+		}
+		if !sliceMeta.elementByPtr {
+			element = element.Elem()
+		}
+		sliceMeta.val.Set(reflect.Append(sliceMeta.val, element))
+	}
+	return nil
+}
+
+// pivotScanner assembles structs from entity-attribute-value rows.
+type pivotScanner struct {
+	api        *API
+	rows       Rows
+	structType reflect.Type
+	mapping    *structMapping
+	// columns contains all column names of the rows.
+	columns        []string
+	entityColumn   string
+	entityPosition int
+	keyPosition    int
+	valuePosition  int
+	// paddedColumns reports whether values of every column are padded, see WithTrimCharPadding.
+	paddedColumns []bool
+	// entityField is the field that the entity column is mapped to, it's nil if there is no such field.
+	entityField    *fieldMeta
+	defaultFields  []*fieldMeta
+	afterScanHooks [][]int
+	// entities maps entity values to indexes of their structs in elements.
+	entities map[interface{}]int
+	elements []reflect.Value
+	// keys contains the keys scanned into every element, they are passed to after scan hooks as columns.
+	keys [][]string
+}
+
+func (api *API) newPivotScanner(rows Rows, structType reflect.Type, entityColumn, keyColumn, valueColumn string,
+) (*pivotScanner, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("scany: get rows columns: %w", err)
+	}
+	mapping, err := api.getStructMapping(structType)
+	if err != nil {
+		return nil, fmt.Errorf("scany: map columns to fields of %v: %w", structType, err)
+	}
+	ps := &pivotScanner{
+		api:            api,
+		rows:           rows,
+		structType:     structType,
+		mapping:        mapping,
+		columns:        columns,
+		entityColumn:   entityColumn,
+		entityField:    mapping.columns[entityColumn],
+		afterScanHooks: mapping.afterScanHooks(structType),
+		entities:       make(map[interface{}]int),
+	}
+	positions := []*int{&ps.entityPosition, &ps.keyPosition, &ps.valuePosition}
+	for i, column := range []string{entityColumn, keyColumn, valueColumn} {
+		*positions[i] = columnPosition(columns, column)
+		if *positions[i] < 0 {
+			return nil, fmt.Errorf("scany: pivot column '%s' isn't found in rows", column)
+		}
+	}
+	if ps.paddedColumns, err = api.paddedColumns(rows); err != nil {
+		return nil, err
+	}
+	seen := make(map[*fieldMeta]struct{}, len(mapping.columns))
+	for _, field := range mapping.columns {
+		if _, ok := seen[field]; !ok && field.Default.IsValid() {
+			ps.defaultFields = append(ps.defaultFields, field)
+		}
+		seen[field] = struct{}{}
+	}
+	return ps, nil
+}
+
+// columnPosition returns the index of the column, it's -1 if columns don't contain it.
+func columnPosition(columns []string, column string) int {
+	for i, c := range columns {
+		if c == column {
+			return i
+		}
+	}
+	return -1
+}
+
+// discardScanTargets returns destinations for Rows.Scan that accept and drop values of all columns.
+func discardScanTargets(columnsNum int) []interface{} {
+	scans := make([]interface{}, columnsNum)
+	for i := range scans {
+		scans[i] = &Discard{}
+	}
+	return scans
+}
+
+func (ps *pivotScanner) scanRow() error {
+	var entity, value interface{}
+	var key *string
+	scans := discardScanTargets(len(ps.columns))
+	scans[ps.valuePosition] = &value
+	scans[ps.keyPosition] = &key
+	scans[ps.entityPosition] = &entity
+	if err := ps.rows.Scan(scans...); err != nil {
+		return fmt.Errorf("scany: scan pivot row: %w", err)
+	}
+	if key == nil {
+		return fmt.Errorf("scany: pivot key column '%s' is NULL", ps.columns[ps.keyPosition])
+	}
+	if ps.entityPosition == ps.valuePosition {
+		value = entity
+	}
+	index, isNew := ps.element(entity)
+	structValue := ps.elements[index].Elem()
+	if isNew && ps.entityField != nil && ps.entityPosition != ps.valuePosition {
+		if err := ps.setField(structValue, ps.entityField, ps.entityColumn, ps.entityPosition, entity); err != nil {
+			return err
+		}
+	}
+	field := ps.mapping.columns[*key]
+	switch {
+	case field != nil && !field.RowUnmarshaler:
+		if err := ps.setField(structValue, field, *key, ps.valuePosition, value); err != nil {
+			return err
+		}
+	case ps.isRemainKey(*key):
+		if err := ps.storeRemain(structValue, *key, value); err != nil {
+			return err
+		}
+	case !ps.api.allowUnknownColumns:
+		return fmt.Errorf("scany: pivot key '%s': no corresponding field found, or it's unexported in %v",
+			*key, ps.structType)
+	}
+	ps.keys[index] = append(ps.keys[index], *key)
+	return nil
+}
+
+// setField assigns the column value of the current row to the field.
+func (ps *pivotScanner) setField(structValue reflect.Value, field *fieldMeta, column string, position int,
+	value interface{},
+) error {
+	initializeNested(structValue, field.Index)
+	fieldVal := structValue.FieldByIndex(field.Index)
+	target, finish := ps.api.fieldScanTarget(fieldVal, column, field)
+	if err := (&pivotValue{target: target}).Scan(value); err != nil {
+		return fmt.Errorf("scany: scan pivot row into struct fields: %w", err)
+	}
+	if finish != nil {
+		// Other rows of the entity might have already made enclosing Null structs valid,
+		// so the validity isn't reset here, unlike for a regular row.
+		if err := finishFields(structValue, []*deferredField{{meta: field, value: fieldVal, finish: finish}}); err != nil {
+			return fmt.Errorf("scany: scan pivot row into struct fields: %w", err)
+		}
+	}
+	if ps.api.normalizationEnabled() {
+		ps.api.normalizeValue(fieldVal, isPaddedColumn(ps.paddedColumns, position))
+	}
+	return nil
+}
+
+// pivotValue converts the value column into the type of the field,
+// because a single value column, often a textual one, holds values of fields of different types.
+type pivotValue struct {
+	target interface{}
+}
+
+// Scan implements the sql.Scanner interface.
+func (pv *pivotValue) Scan(src interface{}) error {
+	return assignValue(reflect.ValueOf(pv.target).Elem(), src)
+}
+
+// element returns the index of the entity struct, the struct is created on the first row of the entity.
+func (ps *pivotScanner) element(entity interface{}) (int, bool) {
+	// Byte slices and other non comparable values are grouped by their string form.
+	if b, ok := entity.([]byte); ok {
+		entity = string(b)
+	} else if entity != nil && !reflect.TypeOf(entity).Comparable() {
+		entity = fmt.Sprint(entity)
+	}
+	if index, ok := ps.entities[entity]; ok {
+		return index, false
+	}
+	structPtr := reflect.New(ps.structType)
+	for _, field := range ps.defaultFields {
+		initializeNested(structPtr.Elem(), field.Index)
+		setDefaultValue(structPtr.Elem().FieldByIndex(field.Index), field.Default)
+	}
+	index := len(ps.elements)
+	ps.entities[entity] = index
+	ps.elements = append(ps.elements, structPtr)
+	ps.keys = append(ps.keys, []string{ps.entityColumn})
+	return index, true
+}
+
+// isRemainKey reports whether the value of the key goes to the remain field.
+func (ps *pivotScanner) isRemainKey(key string) bool {
+	if ps.mapping.remain == nil {
+		return false
+	}
+	_, ok := ps.mapping.remain.key(key)
+	return ok
+}
+
+// storeRemain adds the value of a key without a corresponding field to the remain field.
+// Unlike ScanAll, the remain map collects keys from all rows of the entity.
+func (ps *pivotScanner) storeRemain(structValue reflect.Value, key string, value interface{}) error {
+	remainKey, _ := ps.mapping.remain.key(key)
+	elem := reflect.New(ps.mapping.remain.elemType).Elem()
+	target, finish := ps.api.scanTarget(elem, key, true /* nullable */)
+	if err := (&pivotValue{target: target}).Scan(value); err != nil {
+		return fmt.Errorf("scany: scan pivot row into remain field: %w", err)
+	}
+	if finish != nil {
+		if _, err := finish(); err != nil {
+			return fmt.Errorf("scany: scan pivot row into remain field: %w", err)
+		}
+	}
+	initializeNested(structValue, ps.mapping.remain.Index)
+	field := structValue.FieldByIndex(ps.mapping.remain.Index)
+	if field.IsNil() {
+		field.Set(reflect.MakeMap(field.Type()))
+	}
+	field.SetMapIndex(reflect.ValueOf(remainKey).Convert(field.Type().Key()), elem)
+	return nil
+}
+
+func (ps *pivotScanner) runAfterScanHooks(index int) error {
+	rs := &RowScanner{api: ps.api, columns: ps.keys[index], afterScanHooks: ps.afterScanHooks}
+	return rs.runAfterScanHooks(ps.elements[index].Elem())
+}
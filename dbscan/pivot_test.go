@@ -0,0 +1,128 @@
+package dbscan_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/georgysavva/scany/v2/dbscan"
+)
+
+const pivotRowsQuery = `
+	SELECT *
+	FROM (
+		VALUES (1::INT8, 'foo', 'foo val'), (2, 'foo', 'foo val 2'), (1, 'num', '10'), (2, 'bar', NULL)
+	) AS t (id, key, value)
+`
+
+func TestScanPivot(t *testing.T) {
+	t.Parallel()
+	type entity struct {
+		ID     int64
+		Foo    string
+		Num    *int64
+		Status string  `db:"status,default=on"`
+		Bar    *string `db:"bar"`
+	}
+	num := int64(10)
+	expected := []*entity{
+		{ID: 1, Foo: "foo val", Num: &num, Status: "on"},
+		{ID: 2, Foo: "foo val 2", Status: "on"},
+	}
+
+	got := []*entity{{ID: 3}}
+	err := testAPI.ScanPivot(&got, queryRows(t, pivotRowsQuery), "id", "key", "value")
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
+
+func TestScanPivot_remainField(t *testing.T) {
+	t.Parallel()
+	type entity struct {
+		ID    int64
+		Foo   string
+		Extra map[string]*string `db:",remain"`
+	}
+	expected := []entity{
+		{ID: 1, Foo: "foo val", Extra: map[string]*string{"num": makeStrPtr("10")}},
+		{ID: 2, Foo: "foo val 2", Extra: map[string]*string{"bar": nil}},
+	}
+
+	var got []entity
+	err := testAPI.ScanPivot(&got, queryRows(t, pivotRowsQuery), "id", "key", "value")
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
+
+func TestScanPivot_returnsErr(t *testing.T) {
+	t.Parallel()
+	type entity struct {
+		ID  int64
+		Foo string
+	}
+	cases := []struct {
+		name        string
+		dst         interface{}
+		keyColumn   string
+		expectedErr string
+	}{
+		{
+			name:      "key without field",
+			dst:       &[]entity{},
+			keyColumn: "key",
+			expectedErr: "scanning: scany: pivot key 'num': " +
+				"no corresponding field found, or it's unexported in dbscan_test.entity",
+		},
+		{
+			name:        "absent column",
+			dst:         &[]entity{},
+			keyColumn:   "attribute",
+			expectedErr: "scany: pivot column 'attribute' isn't found in rows",
+		},
+		{
+			name:        "non struct element",
+			dst:         &[]string{},
+			keyColumn:   "key",
+			expectedErr: "scany: pivot destination element must be a struct, got: string",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := testAPI.ScanPivot(tc.dst, queryRows(t, pivotRowsQuery), "id", tc.keyColumn, "value")
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestScanPivot_nullStructField(t *testing.T) {
+	t.Parallel()
+	type address struct {
+		City string
+		Zip  *string
+	}
+	type entity struct {
+		ID      int64
+		Address dbscan.Null[address]
+	}
+	query := `
+		SELECT *
+		FROM (
+			VALUES (1::INT8, 'address.city', 'city val'), (1, 'address.zip', NULL), (2, 'address.zip', NULL)
+		) AS t (id, key, value)
+	`
+	expected := []entity{
+		{ID: 1, Address: dbscan.NewNull(address{City: "city val"})},
+		{ID: 2},
+	}
+
+	var got []entity
+	err := testAPI.ScanPivot(&got, queryRows(t, query), "id", "key", "value")
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
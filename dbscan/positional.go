@@ -0,0 +1,155 @@
+package dbscan
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Positional is a marker that makes dbscan bind struct fields to columns by their ordinal instead of names.
+// Embed it into the destination struct:
+//
+//	type Stats struct {
+//		dbscan.Positional
+//		Count int
+//		Max   *time.Time
+//	}
+//
+// Exported fields are bound to columns in the order they are declared, fields ignored with the "-" tag are skipped.
+// Tag names are ignored, but tag options apply.
+// Fields aren't traversed, so a nested struct is bound to a single column.
+// Rows must contain exactly as many columns as there are fields, column names don't matter and may repeat.
+//
+// To bind a single field of a regular struct to a column by ordinal use the "#N" tag name,
+// for example `db:"#0"` binds the field to the first column.
+type Positional struct{}
+
+var positionalType = reflect.TypeOf(Positional{})
+
+// positionPrefix starts the column ordinal in the tag name.
+const positionPrefix = "#"
+
+func isPositionalStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Anonymous && field.Type == positionalType {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePosition parses the column ordinal from the tag name, like "#0".
+func parsePosition(name string) (int, bool) {
+	if !strings.HasPrefix(name, positionPrefix) {
+		return 0, false
+	}
+	position, err := strconv.ParseUint(name[len(positionPrefix):], 10, 31)
+	if err != nil {
+		return 0, false
+	}
+	return int(position), true
+}
+
+// addPosition binds the field to the column by its ordinal.
+func (m *structMapping) addPosition(field reflect.StructField, position int, meta *fieldMeta) error {
+	if _, exists := m.positions[position]; exists {
+		return fmt.Errorf("scany: field %s: column #%d is bound to more than one field", field.Name, position)
+	}
+	if m.positions == nil {
+		m.positions = make(map[int]*fieldMeta)
+	}
+	m.positions[position] = meta
+	return nil
+}
+
+// getPositionalMapping binds fields of the positional struct to columns in the order they are declared.
+func (api *API) getPositionalMapping(structType reflect.Type) (*structMapping, error) {
+	result := &structMapping{columns: map[string]*fieldMeta{}, positional: true}
+	var position int
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" || field.Type == positionalType {
+			continue
+		}
+		tag, _ := api.LookupTag(field)
+		if tag.Ignored() {
+			continue
+		}
+		meta, err := api.newFieldMeta(field, field.Index, nil /* nullIndexes */, tag)
+		if err != nil {
+			return nil, err
+		}
+		if err := result.addPosition(field, position, meta); err != nil {
+			return nil, err
+		}
+		position++
+	}
+	return result, nil
+}
+
+// getColumnFields returns the fields for all columns of the current rows,
+// it's nil for columns without a corresponding field.
+func (rs *RowScanner) getColumnFields(mapping *structMapping) ([]*fieldMeta, error) {
+	if mapping.positional && len(mapping.positions) != len(rs.columns) {
+		return nil, fmt.Errorf("scany: positional struct has %d fields, but rows contain %d columns",
+			len(mapping.positions), len(rs.columns))
+	}
+	fields := make([]*fieldMeta, len(rs.columns))
+	for position, field := range mapping.positions {
+		if position >= len(rs.columns) {
+			return nil, fmt.Errorf("scany: column #%d is out of range, rows contain %d columns",
+				position, len(rs.columns))
+		}
+		fields[position] = field
+	}
+	for i, column := range rs.columns {
+		if fields[i] == nil {
+			fields[i] = rs.columnToFieldIndex[column]
+		}
+	}
+	return fields, nil
+}
+
+// rowValues is the destination of ScanOneValues, every element is a pointer that receives the column value.
+type rowValues []interface{}
+
+var rowValuesType = reflect.TypeOf(rowValues{})
+
+func (rs *RowScanner) scanValues(dstValue reflect.Value) error {
+	dst := dstValue.Interface().(rowValues)
+	if len(dst) != len(rs.columns) {
+		return fmt.Errorf("scany: got %d destinations, but rows contain %d columns", len(dst), len(rs.columns))
+	}
+	values := make([]reflect.Value, len(dst))
+	scans := make([]interface{}, len(dst))
+	finishes := make([]finishScanFunc, len(dst))
+	for i, d := range dst {
+		value, err := parseDestination(d)
+		if err != nil {
+			return fmt.Errorf("parsing destination #%d: %w", i, err)
+		}
+		values[i] = value
+		scans[i], finishes[i] = rs.api.scanTarget(value, rs.columns[i], false /* nullable */)
+	}
+	if err := rs.rows.Scan(scans...); err != nil {
+		return fmt.Errorf("scany: scan row into values: %w", err)
+	}
+	for _, finish := range finishes {
+		if finish == nil {
+			continue
+		}
+		if _, err := finish(); err != nil {
+			return fmt.Errorf("scany: scan row into values: %w", err)
+		}
+	}
+	if rs.api.normalizationEnabled() {
+		for i, value := range values {
+			rs.api.normalizeValue(value, isPaddedColumn(rs.paddedColumns, i))
+		}
+	}
+	return nil
+}
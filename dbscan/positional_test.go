@@ -0,0 +1,168 @@
+package dbscan_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/georgysavva/scany/v2/dbscan"
+)
+
+func TestRowScanner_Scan_positional(t *testing.T) {
+	t.Parallel()
+	type Stats struct {
+		dbscan.Positional
+		Count   int
+		Ignored string `db:"-"`
+		MaxName *string
+		Status  string `db:"any name,default=active"`
+	}
+	type Mixed struct {
+		First string `db:"#0"`
+		Bar   string
+	}
+	type Counts struct {
+		Total    int `db:"#0"`
+		Distinct int `db:"#1"`
+	}
+	cases := []struct {
+		name     string
+		query    string
+		expected interface{}
+	}{
+		{
+			name:     "positional struct with duplicate column names",
+			query:    `SELECT 2 AS foo, 'bar val' AS foo, NULL AS foo`,
+			expected: &Stats{Count: 2, MaxName: makeStrPtr("bar val"), Status: "active"},
+		},
+		{
+			name:     "column index in struct tag",
+			query:    `SELECT 'foo val' AS foo, 'bar val' AS bar`,
+			expected: &Mixed{First: "foo val", Bar: "bar val"},
+		},
+		{
+			name:     "column indexes in struct tags with duplicate column names",
+			query:    `SELECT 3 AS count, 2 AS count`,
+			expected: &Counts{Total: 3, Distinct: 2},
+		},
+		{
+			name:     "column bound by index duplicates a column bound by name",
+			query:    `SELECT 'foo val' AS bar, 'bar val' AS bar`,
+			expected: &Mixed{First: "foo val", Bar: "bar val"},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rows := queryRows(t, tc.query)
+			dst := allocateDestination(tc.expected)
+			err := scan(t, dst, rows)
+			require.NoError(t, err)
+			assertDestinationEqual(t, tc.expected, dst)
+		})
+	}
+}
+
+func TestRowScanner_Scan_invalidPositional_returnsErr(t *testing.T) {
+	t.Parallel()
+	type Stats struct {
+		dbscan.Positional
+		Count int
+		Max   int
+	}
+	type Duplicate struct {
+		First  string `db:"#0"`
+		Second string `db:"#0"`
+	}
+	type OutOfRange struct {
+		Foo string `db:"#2"`
+	}
+	type Mixed struct {
+		First string `db:"#0"`
+		Bar   string
+	}
+	cases := []struct {
+		name        string
+		query       string
+		dst         interface{}
+		expectedErr string
+	}{
+		{
+			name:        "fields number doesn't match columns",
+			query:       `SELECT 1 AS foo`,
+			dst:         &Stats{},
+			expectedErr: "scany: positional struct has 2 fields, but rows contain 1 columns",
+		},
+		{
+			name:  "column index is bound twice",
+			query: singleRowsQuery,
+			dst:   &Duplicate{},
+			expectedErr: "scany: map columns to fields of dbscan_test.Duplicate: " +
+				"scany: field Second: column #0 is bound to more than one field",
+		},
+		{
+			name:        "column index out of range",
+			query:       singleRowsQuery,
+			dst:         &OutOfRange{},
+			expectedErr: "scany: column #2 is out of range, rows contain 2 columns",
+		},
+		{
+			name:        "duplicate columns bound by name",
+			query:       `SELECT 'foo val' AS foo, 'bar val' AS bar, 'bar val 2' AS bar`,
+			dst:         &Mixed{},
+			expectedErr: "duplicate columns: scany: rows contain a duplicate column 'bar'",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rows := queryRows(t, tc.query)
+			err := scan(t, tc.dst, rows)
+			assert.EqualError(t, err, "doing scan: starting: "+tc.expectedErr)
+		})
+	}
+}
+
+func TestScanOneValues(t *testing.T) {
+	t.Parallel()
+	rows := queryRows(t, `SELECT 'foo val' AS foo, NULL AS foo`)
+	var foo string
+	var bar *string
+	err := testAPI.ScanOneValues(rows, &foo, &bar)
+	require.NoError(t, err)
+	assert.Equal(t, "foo val", foo)
+	assert.Nil(t, bar)
+}
+
+func TestScanOneValues_invalidDestinations_returnsErr(t *testing.T) {
+	t.Parallel()
+	var foo string
+	cases := []struct {
+		name        string
+		dst         []interface{}
+		expectedErr string
+	}{
+		{
+			name:        "destinations number doesn't match columns",
+			dst:         []interface{}{&foo},
+			expectedErr: "scany: got 1 destinations, but rows contain 2 columns",
+		},
+		{
+			name:        "destination isn't a pointer",
+			dst:         []interface{}{&foo, foo},
+			expectedErr: "parsing destination #1: scany: destination must be a pointer, got: string",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rows := queryRows(t, singleRowsQuery)
+			err := testAPI.ScanOneValues(rows, tc.dst...)
+			assert.EqualError(t, err, "scanning: doing scan: scanFn: "+tc.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package dbscan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Record is a row with ordered columns, it's useful for dynamic results
+// when the set of columns isn't known in advance, like in generic admin or reporting tools.
+// Unlike map[string]interface{} it preserves the column order and duplicate columns.
+//
+// Record is a valid destination for ScanOne and RowScanner.Scan,
+// []Record and []*Record are valid destinations for ScanAll.
+// Records scanned from the same rows share the Columns slice, so it shouldn't be modified.
+type Record struct {
+	// Columns contains the column names in the order they are returned by the database.
+	Columns []string
+	// Values contains the column values, Values[i] is the value of Columns[i], NULL is nil.
+	Values []interface{}
+}
+
+// Tuple is a row of column values in the column order, it's useful for purely positional results.
+// NULL is nil. Tuple is a valid destination for ScanOne and RowScanner.Scan, []Tuple is one for ScanAll.
+//
+// Unlike []interface{}, which is handled as a single column value, like an array,
+// Tuple receives all columns regardless of their number.
+type Tuple []interface{}
+
+var (
+	recordType         = reflect.TypeOf(Record{})
+	tupleType          = reflect.TypeOf(Tuple{})
+	interfaceSliceType = reflect.TypeOf([]interface{}{})
+)
+
+// Get returns the value of the column and reports whether the record contains the column.
+// If the column is repeated, the first one is used.
+func (r Record) Get(column string) (interface{}, bool) {
+	for i, c := range r.Columns {
+		if c == column {
+			return r.Values[i], true
+		}
+	}
+	return nil, false
+}
+
+// IsNull reports whether the column value is NULL. It's false if the record doesn't contain the column.
+func (r Record) IsNull(column string) bool {
+	value, ok := r.Get(column)
+	return ok && value == nil
+}
+
+// String returns the column value as a string, numbers and []byte values are converted.
+func (r Record) String(column string) (string, error) {
+	var s string
+	err := r.assign(column, &s)
+	return s, err
+}
+
+// Int64 returns the column value as an int64, other numeric types and numeric strings are converted.
+func (r Record) Int64(column string) (int64, error) {
+	var n int64
+	err := r.assign(column, &n)
+	return n, err
+}
+
+// Time returns the column value as a time.Time, strings in common database formats are parsed.
+func (r Record) Time(column string) (time.Time, error) {
+	var t time.Time
+	err := r.assign(column, &t)
+	return t, err
+}
+
+// assign converts the column value into the type of dst, it returns an error if the value is NULL.
+func (r Record) assign(column string, dst interface{}) error {
+	value, ok := r.Get(column)
+	if !ok {
+		return fmt.Errorf("scany: record doesn't contain column %q", column)
+	}
+	if value == nil {
+		return fmt.Errorf("scany: column %q is NULL", column)
+	}
+	if err := assignValue(reflect.ValueOf(dst).Elem(), value); err != nil {
+		return fmt.Errorf("scany: column %q: %w", column, err)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the record as a JSON object with keys in the column order.
+func (r Record) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, column := range r.Columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := json.Marshal(r.Values[i])
+		if err != nil {
+			return nil, fmt.Errorf("scany: column %q: %w", column, err)
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (rs *RowScanner) scanRecord(recordValue reflect.Value) error {
+	values, err := rs.scanInterfaces()
+	if err != nil {
+		return fmt.Errorf("scany: scan row into record: %w", err)
+	}
+	recordValue.Set(reflect.ValueOf(Record{Columns: rs.columns, Values: values}))
+	return nil
+}
+
+func (rs *RowScanner) scanTuple(tupleValue reflect.Value) error {
+	values, err := rs.scanInterfaces()
+	if err != nil {
+		return fmt.Errorf("scany: scan row into tuple: %w", err)
+	}
+	tupleValue.Set(reflect.ValueOf(Tuple(values)))
+	return nil
+}
+
+// scanInterfaces scans the current row into interface values in the column order.
+func (rs *RowScanner) scanInterfaces() ([]interface{}, error) {
+	values := make([]interface{}, len(rs.columns))
+	scans := make([]interface{}, len(rs.columns))
+	var finishers []finishScanFunc
+	for i := range rs.columns {
+		var finish finishScanFunc
+		scans[i], finish = rs.interfaceScanTarget(reflect.ValueOf(&values[i]).Elem(), i)
+		if finish != nil {
+			finishers = append(finishers, finish)
+		}
+	}
+	if err := rs.rows.Scan(scans...); err != nil {
+		return nil, err
+	}
+	for _, finish := range finishers {
+		if _, err := finish(); err != nil {
+			return nil, err
+		}
+	}
+	if rs.api.normalizationEnabled() {
+		for i := range values {
+			rs.api.normalizeValue(reflect.ValueOf(&values[i]).Elem(), isPaddedColumn(rs.paddedColumns, i))
+		}
+	}
+	return values, nil
+}
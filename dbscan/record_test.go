@@ -0,0 +1,93 @@
+package dbscan_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/georgysavva/scany/v2/dbscan"
+)
+
+func TestRowScanner_Scan_record(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT 'foo val' AS foo, 2::INT8 AS num, NULL AS foo, '2020-01-02 03:04:05+00'::TIMESTAMPTZ AS ts
+	`
+	rows := queryRows(t, query)
+	var got dbscan.Record
+	err := scan(t, &got, rows)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"foo", "num", "foo", "ts"}, got.Columns)
+	s, err := got.String("foo")
+	require.NoError(t, err)
+	assert.Equal(t, "foo val", s)
+	n, err := got.Int64("num")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	ts, err := got.Time("ts")
+	require.NoError(t, err)
+	assert.True(t, ts.Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)))
+	assert.False(t, got.IsNull("foo"))
+	assert.Nil(t, got.Values[2])
+
+	_, err = got.Int64("missing")
+	assert.EqualError(t, err, `scany: record doesn't contain column "missing"`)
+	_, err = got.Int64("foo")
+	assert.EqualError(t, err,
+		`scany: column "foo": scany: parse "foo val" into int64: strconv.ParseInt: parsing "foo val": invalid syntax`)
+}
+
+func TestScanAll_records(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT *
+		FROM (
+			VALUES ('foo val', 1::INT8), ('foo val 2', NULL)
+		) AS t (foo, bar)
+	`
+	var records []*dbscan.Record
+	err := testAPI.ScanAll(&records, queryRows(t, query))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.True(t, records[1].IsNull("bar"))
+
+	data, err := json.Marshal(records)
+	require.NoError(t, err)
+	// Keys are in the column order.
+	assert.Equal(t, `[{"foo":"foo val","bar":1},{"foo":"foo val 2","bar":null}]`, string(data))
+
+	var tuples []dbscan.Tuple
+	err = testAPI.ScanAll(&tuples, queryRows(t, query))
+	require.NoError(t, err)
+	assert.Equal(t, []dbscan.Tuple{{"foo val", int64(1)}, {"foo val 2", nil}}, tuples)
+}
+
+func TestScanAll_tuple_singleColumn(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT * FROM (
+			VALUES ('foo val'), ('foo val 2')
+		) AS t (foo)
+	`
+	var tuples []dbscan.Tuple
+	err := testAPI.ScanAll(&tuples, queryRows(t, query))
+	require.NoError(t, err)
+	assert.Equal(t, []dbscan.Tuple{{"foo val"}, {"foo val 2"}}, tuples)
+}
+
+func TestScanAll_interfaceSlice_singleArrayColumn(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT * FROM (
+			VALUES (ARRAY['foo val', 'bar val']), (ARRAY['foo val 2'])
+		) AS t (foo)
+	`
+	var values [][]interface{}
+	err := testAPI.ScanAll(&values, queryRows(t, query))
+	require.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{"foo val", "bar val"}, {"foo val 2"}}, values)
+}
@@ -0,0 +1,102 @@
+package dbscan
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// remainField is the field that collects columns without a corresponding field, see TagOptionRemain.
+type remainField struct {
+	// Index is the index sequence of the field, see reflect.Value.FieldByIndex.
+	Index []int
+	// prefix is the beginning of column names of the struct that contains the field, it's empty for the root struct.
+	// Only columns with the prefix are collected, the prefix is removed from the map keys.
+	prefix string
+	// elemType is the map element type, column values are scanned into it.
+	elemType reflect.Type
+}
+
+// key returns the map key of the column, it reports false if the column isn't in the scope of the field.
+func (rf *remainField) key(column string) (string, bool) {
+	if !strings.HasPrefix(column, rf.prefix) || len(column) == len(rf.prefix) {
+		return "", false
+	}
+	return column[len(rf.prefix):], true
+}
+
+// setRemain sets the field that collects columns without a corresponding field.
+func (m *structMapping) setRemain(field reflect.StructField, index []int, prefix string) error {
+	if m.remain != nil {
+		return fmt.Errorf("scany: field %s: only one field can have %q tag option", field.Name, TagOptionRemain)
+	}
+	if field.Type.Kind() != reflect.Map || field.Type.Key().Kind() != reflect.String {
+		return fmt.Errorf("scany: field %s: %q tag option requires a map with string keys, got %v",
+			field.Name, TagOptionRemain, field.Type)
+	}
+	m.remain = &remainField{Index: index, prefix: prefix, elemType: field.Type.Elem()}
+	return nil
+}
+
+// remainValue is a column value scanned for the remain field.
+type remainValue struct {
+	// key is the map key of the value, see remainField.key.
+	key string
+	// position is the index of the value column in the row.
+	position int
+	value    reflect.Value
+	finish   finishScanFunc
+}
+
+// unknownColumnTarget returns the destination for Rows.Scan for the column without a corresponding field.
+// If the struct has the remain field, the value is scanned into the map element type.
+func (rs *RowScanner) unknownColumnTarget(structValue reflect.Value, position int) (
+	interface{}, *remainValue, error,
+) {
+	column := rs.columns[position]
+	if key, ok := rs.remainKey(column); ok {
+		rv := &remainValue{key: key, position: position, value: reflect.New(rs.remainField.elemType).Elem()}
+		var target interface{}
+		target, rv.finish = rs.api.scanTarget(rv.value, column, true /* nullable */)
+		return target, rv, nil
+	}
+	if rs.api.allowUnknownColumns {
+		return &Discard{}, nil, nil
+	}
+	return nil, nil, fmt.Errorf(
+		"scany: column: '%s': no corresponding field found, or it's unexported in %v",
+		column, structValue.Type(),
+	)
+}
+
+// remainKey returns the key of the column in the remain field, it reports false if there is no such field.
+func (rs *RowScanner) remainKey(column string) (string, bool) {
+	if rs.remainField == nil {
+		return "", false
+	}
+	return rs.remainField.key(column)
+}
+
+// fillRemainField stores values of columns without a corresponding field into the remain field.
+// The remain field gets a new map for every row, so destinations don't share it.
+func (rs *RowScanner) fillRemainField(structValue reflect.Value, remain []*remainValue) error {
+	if len(remain) == 0 {
+		return nil
+	}
+	initializeNested(structValue, rs.remainField.Index)
+	field := structValue.FieldByIndex(rs.remainField.Index)
+	m := reflect.MakeMapWithSize(field.Type(), len(remain))
+	for _, rv := range remain {
+		if rv.finish != nil {
+			if _, err := rv.finish(); err != nil {
+				return fmt.Errorf("scany: scan row into struct fields: %w", err)
+			}
+		}
+		if rs.api.normalizationEnabled() {
+			rs.api.normalizeValue(rv.value, isPaddedColumn(rs.paddedColumns, rv.position))
+		}
+		m.SetMapIndex(reflect.ValueOf(rv.key).Convert(field.Type().Key()), rv.value)
+	}
+	field.Set(m)
+	return nil
+}
@@ -1,8 +1,11 @@
 package dbscan
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 )
 
 type startScannerFunc func(rs *RowScanner, dstValue reflect.Value) error
@@ -29,11 +32,28 @@ type RowScanner struct {
 	api                *API
 	rows               Rows
 	columns            []string
-	columnToFieldIndex map[string][]int
-	mapElementType     reflect.Type
-	started            bool
-	scanFn             func(dstVal reflect.Value) error
-	start              startScannerFunc
+	columnToFieldIndex map[string]*fieldMeta
+	// columnFields contains the field for every column of the rows, it's nil if the column doesn't have one.
+	columnFields []*fieldMeta
+	// absentDefaultFields contains fields that have a default value but their columns aren't present in rows.
+	absentDefaultFields []*fieldMeta
+	remainField         *remainField
+	wildcardColumns     []*wildcardColumns
+	unmarshalerFields   []*unmarshalerField
+	// unmarshaledColumns contains columns that are scanned by nested RowUnmarshaler fields.
+	unmarshaledColumns map[string]struct{}
+	// afterScanHooks contains indexes of nested structs that implement after scan interfaces.
+	afterScanHooks [][]int
+	// valueTypes contains Go types for columns scanned into interface values, see ValueTypesRows.
+	valueTypes     []reflect.Type
+	mapElementType reflect.Type
+	// paddedColumns reports whether values of every column are padded, see WithTrimCharPadding.
+	paddedColumns []bool
+	// sparseFields allows rows to lack columns of required fields, see WithSparseFields.
+	sparseFields bool
+	started      bool
+	scanFn       func(dstVal reflect.Value) error
+	start        startScannerFunc
 }
 
 // NewRowScanner is a package-level helper function that uses the DefaultAPI object.
@@ -70,11 +90,18 @@ func (rs *RowScanner) doScan(dstValue reflect.Value) error {
 		if err := rs.start(rs, dstValue); err != nil {
 			return fmt.Errorf("starting: %w", err)
 		}
+		var err error
+		if rs.paddedColumns, err = rs.api.paddedColumns(rs.rows); err != nil {
+			return fmt.Errorf("starting: %w", err)
+		}
 		rs.started = true
 	}
 	if err := rs.scanFn(dstValue); err != nil {
 		return fmt.Errorf("scanFn: %w", err)
 	}
+	if err := rs.runAfterScanHooks(dstValue); err != nil {
+		return fmt.Errorf("after scan: %w", err)
+	}
 	return nil
 }
 
@@ -84,21 +111,39 @@ func startScanner(rs *RowScanner, dstValue reflect.Value) error {
 	if err != nil {
 		return fmt.Errorf("scany: get rows columns: %w", err)
 	}
-	if err := rs.ensureDistinctColumns(); err != nil {
-		return fmt.Errorf("duplicate columns: %w", err)
-	}
 	dstKind := dstValue.Kind()
 	dstType := dstValue.Type()
+	// Positional destinations don't depend on column names.
+	switch {
+	case dstType == rowValuesType:
+		rs.scanFn = rs.scanValues
+		return nil
+	case dstType == recordType:
+		rs.scanFn = rs.scanRecord
+		return rs.startValueTypes()
+	case dstType == tupleType:
+		rs.scanFn = rs.scanTuple
+		return rs.startValueTypes()
+	}
 	isScannable := rs.api.isScannableType(dstType)
+	isUnmarshaler := implementsRowUnmarshaler(dstType)
+	// Struct destinations are checked in startStruct, since columns bound by ordinal may repeat.
+	if dstKind != reflect.Struct || isScannable || isUnmarshaler {
+		if err := rs.ensureDistinctColumns(nil /* positions */); err != nil {
+			return fmt.Errorf("duplicate columns: %w", err)
+		}
+	}
+	if isUnmarshaler {
+		rs.scanFn = rs.scanRowUnmarshaler
+		return nil
+	}
 	if isScannable && len(rs.columns) == 1 {
 		rs.scanFn = rs.scanPrimitive
 		return nil
 	}
 
 	if dstKind == reflect.Struct {
-		rs.columnToFieldIndex = rs.api.getColumnToFieldIndexMap(dstType)
-		rs.scanFn = rs.scanStruct
-		return nil
+		return rs.startStruct(dstType)
 	}
 
 	if dstKind == reflect.Map {
@@ -110,7 +155,7 @@ func startScanner(rs *RowScanner, dstValue reflect.Value) error {
 		}
 		rs.mapElementType = dstType.Elem()
 		rs.scanFn = rs.scanMap
-		return nil
+		return rs.startValueTypes()
 	}
 
 	if len(rs.columns) == 1 {
@@ -123,35 +168,168 @@ func startScanner(rs *RowScanner, dstValue reflect.Value) error {
 	)
 }
 
+func (rs *RowScanner) startStruct(dstType reflect.Type) error {
+	mapping, err := rs.api.getStructMapping(dstType)
+	if err != nil {
+		return fmt.Errorf("scany: map columns to fields of %v: %w", dstType, err)
+	}
+	rs.columnToFieldIndex = mapping.columns
+	rs.remainField = mapping.remain
+	rs.columnFields, err = rs.getColumnFields(mapping)
+	if err != nil {
+		return err
+	}
+	if err := rs.ensureDistinctColumns(mapping.positions); err != nil {
+		return fmt.Errorf("duplicate columns: %w", err)
+	}
+	if err := rs.checkColumnTypes(dstType); err != nil {
+		return err
+	}
+	present, err := rs.getPresentFields()
+	if err != nil {
+		return err
+	}
+	if err := rs.ensureRequiredColumns(present); err != nil {
+		return err
+	}
+	rs.absentDefaultFields = rs.getAbsentDefaultFields(present)
+	rs.unmarshalerFields = rs.getUnmarshalerFields()
+	rs.wildcardColumns = rs.getWildcardColumns(mapping.wildcards)
+	rs.afterScanHooks = mapping.afterScanHooks(dstType)
+	rs.scanFn = rs.scanStruct
+	return nil
+}
+
 func (rs *RowScanner) scanStruct(structValue reflect.Value) error {
+	for _, field := range rs.absentDefaultFields {
+		initializeNested(structValue, field.Index)
+		setDefaultValue(structValue.FieldByIndex(field.Index), field.Default)
+	}
 	scans := make([]interface{}, len(rs.columns))
+	// Columns of wildcard and nested RowUnmarshaler fields get their scan targets first.
+	wildcards := rs.wildcardScanTargets(scans)
+	rs.unmarshalerScanTargets(scans)
+	var deferred []*deferredField
+	var remain []*remainValue
 	for i, column := range rs.columns {
-		fieldIndex, ok := rs.columnToFieldIndex[column]
-		if !ok {
-			if rs.api.allowUnknownColumns {
-				var tmp interface{}
-				scans[i] = &tmp
-				continue
+		if scans[i] != nil {
+			continue
+		}
+		field := rs.columnFields[i]
+		if field == nil {
+			var rv *remainValue
+			var err error
+			scans[i], rv, err = rs.unknownColumnTarget(structValue, i)
+			if err != nil {
+				return err
 			}
-			return fmt.Errorf(
-				"scany: column: '%s': no corresponding field found, or it's unexported in %v",
-				column, structValue.Type(),
-			)
+			if rv != nil {
+				remain = append(remain, rv)
+			}
+			continue
 		}
 		// Struct may contain embedded structs by ptr that defaults to nil.
 		// In order to scan values into a nested field,
 		// we need to initialize all nil structs on its way.
-		initializeNested(structValue, fieldIndex)
+		initializeNested(structValue, field.Index)
 
-		fieldVal := structValue.FieldByIndex(fieldIndex)
-		scans[i] = fieldVal.Addr().Interface()
+		fieldVal := structValue.FieldByIndex(field.Index)
+		var finish finishScanFunc
+		scans[i], finish = rs.api.fieldScanTarget(fieldVal, column, field)
+		if finish == nil {
+			continue
+		}
+		df := &deferredField{meta: field, value: fieldVal, finish: finish}
+		if isNullType(fieldVal.Type()) {
+			// Null field must be finished before its nested fields,
+			// so it won't reset the value that nested fields were scanned into.
+			deferred = append([]*deferredField{df}, deferred...)
+		} else {
+			deferred = append(deferred, df)
+		}
 	}
 	if err := rs.rows.Scan(scans...); err != nil {
 		return fmt.Errorf("scany: scan row into struct fields: %w", err)
 	}
+	if err := finishDeferredFields(structValue, deferred); err != nil {
+		return fmt.Errorf("scany: scan row into struct fields: %w", err)
+	}
+	if err := rs.fillWildcardFields(structValue, wildcards); err != nil {
+		return err
+	}
+	if err := rs.fillRemainField(structValue, remain); err != nil {
+		return err
+	}
+	for _, uf := range rs.unmarshalerFields {
+		if err := rs.scanUnmarshalerField(structValue, uf); err != nil {
+			return err
+		}
+	}
+	if rs.api.normalizationEnabled() {
+		rs.normalizeStruct(structValue)
+	}
 	return nil
 }
 
+// normalizeStruct post-processes all fields that were filled from the current row.
+func (rs *RowScanner) normalizeStruct(structValue reflect.Value) {
+	for _, field := range rs.absentDefaultFields {
+		rs.api.normalizeValue(structValue.FieldByIndex(field.Index), false /* trimPadding */)
+	}
+	for i, field := range rs.columnFields {
+		if field != nil && !field.RowUnmarshaler {
+			rs.api.normalizeValue(structValue.FieldByIndex(field.Index), isPaddedColumn(rs.paddedColumns, i))
+		}
+	}
+}
+
+// deferredField is a struct field that wasn't scanned directly and requires processing after Rows.Scan.
+type deferredField struct {
+	meta   *fieldMeta
+	value  reflect.Value
+	finish finishScanFunc
+}
+
+func finishDeferredFields(structValue reflect.Value, deferred []*deferredField) error {
+	for _, df := range deferred {
+		for _, nullIndex := range df.meta.NullIndexes {
+			structValue.FieldByIndex(nullIndex).Field(nullValidFieldIndex).SetBool(false)
+		}
+	}
+	return finishFields(structValue, deferred)
+}
+
+// finishFields finishes the deferred fields, it makes enclosing Null structs valid if the field isn't NULL.
+// Unlike finishDeferredFields, it doesn't reset the validity first,
+// so the struct can be assembled from several rows, like ScanPivot does.
+func finishFields(structValue reflect.Value, deferred []*deferredField) error {
+	for _, df := range deferred {
+		notNull, err := df.finish()
+		if err != nil {
+			return err
+		}
+		if notNull {
+			for _, nullIndex := range df.meta.NullIndexes {
+				structValue.FieldByIndex(nullIndex).Field(nullValidFieldIndex).SetBool(true)
+			}
+		} else if df.meta.Default.IsValid() {
+			setDefaultValue(df.value, df.meta.Default)
+		}
+	}
+	return nil
+}
+
+// setDefaultValue sets the default value to the field.
+// Pointer defaults are copied, so destinations don't share the same default value.
+func setDefaultValue(fieldVal, defaultValue reflect.Value) {
+	if defaultValue.Kind() == reflect.Ptr {
+		v := reflect.New(defaultValue.Type().Elem())
+		v.Elem().Set(defaultValue.Elem())
+		defaultValue = v
+	}
+	fieldVal.Set(defaultValue)
+}
+
 func (rs *RowScanner) scanMap(mapValue reflect.Value) error {
 	if mapValue.IsNil() {
 		mapValue.Set(reflect.MakeMap(mapValue.Type()))
@@ -159,35 +337,209 @@ func (rs *RowScanner) scanMap(mapValue reflect.Value) error {
 
 	scans := make([]interface{}, len(rs.columns))
 	values := make([]reflect.Value, len(rs.columns))
+	var finishers []finishScanFunc
 	for i := range rs.columns {
-		valuePtr := reflect.New(rs.mapElementType)
-		scans[i] = valuePtr.Interface()
-		values[i] = valuePtr.Elem()
+		values[i] = reflect.New(rs.mapElementType).Elem()
+		var finish finishScanFunc
+		scans[i], finish = rs.interfaceScanTarget(values[i], i)
+		if finish != nil {
+			finishers = append(finishers, finish)
+		}
 	}
 	if err := rs.rows.Scan(scans...); err != nil {
 		return fmt.Errorf("scany: scan rows into map: %w", err)
 	}
+	for _, finish := range finishers {
+		if _, err := finish(); err != nil {
+			return fmt.Errorf("scany: scan rows into map: %w", err)
+		}
+	}
 	// We can't set reflect values into destination map before scanning them,
 	// because reflect will set a copy, just like regular map behaves,
 	// and scan won't modify the map element.
 	for i, column := range rs.columns {
 		key := reflect.ValueOf(column)
 		value := values[i]
+		if rs.api.normalizationEnabled() {
+			rs.api.normalizeValue(value, isPaddedColumn(rs.paddedColumns, i))
+		}
 		mapValue.SetMapIndex(key, value)
 	}
 	return nil
 }
 
 func (rs *RowScanner) scanPrimitive(value reflect.Value) error {
-	if err := rs.rows.Scan(value.Addr().Interface()); err != nil {
+	scan, finish := rs.api.scanTarget(value, rs.columns[0], false /* nullable */)
+	if err := rs.rows.Scan(scan); err != nil {
 		return fmt.Errorf("scany: scan row value into a primitive type: %w", err)
 	}
+	if finish != nil {
+		if _, err := finish(); err != nil {
+			return fmt.Errorf("scany: scan row value into a primitive type: %w", err)
+		}
+	}
+	if rs.api.normalizationEnabled() {
+		rs.api.normalizeValue(value, isPaddedColumn(rs.paddedColumns, 0))
+	}
+	return nil
+}
+
+// finishScanFunc must be called after Rows.Scan to store the scanned value into the destination.
+// It reports whether the scanned value wasn't NULL.
+type finishScanFunc func() (notNull bool, err error)
+
+// scanTarget returns the destination for Rows.Scan that fills the value from the column.
+// If the value requires processing after scanning, it also returns a non nil finish function.
+// If nullable is true, the value is scanned in a way that allows to find out whether it was NULL.
+func (api *API) scanTarget(value reflect.Value, column string, nullable bool) (interface{}, finishScanFunc) {
+	if scan, finish := api.enumScanTarget(value, column); finish != nil {
+		return scan, finish
+	}
+	if isNullType(value.Type()) {
+		return nullScanTarget(value)
+	}
+	if nullable {
+		return nullableScanTarget(value)
+	}
+	return value.Addr().Interface(), nil
+}
+
+// fieldScanTarget is the same as scanTarget, but it also takes struct tag options of the field into account.
+func (api *API) fieldScanTarget(value reflect.Value, column string, field *fieldMeta) (interface{}, finishScanFunc) {
+	if field.JSON {
+		return jsonScanTarget(value, column)
+	}
+	// We need to know whether the value is NULL to decide if the enclosing Null field is valid
+	// or if the field should receive the default value.
+	nullable := len(field.NullIndexes) > 0 || field.Default.IsValid() || field.NullZero
+	return api.scanTarget(value, column, nullable)
+}
+
+// jsonScanTarget returns a destination for Rows.Scan that decodes the column value as JSON into the value.
+// NULL resets the value to zero.
+func jsonScanTarget(value reflect.Value, column string) (interface{}, finishScanFunc) {
+	var data []byte
+	return &data, func() (bool, error) {
+		target := value
+		isNull := isNullType(value.Type())
+		if isNull {
+			target = value.Field(nullValueFieldIndex)
+		}
+		target.Set(reflect.Zero(target.Type()))
+		notNull := data != nil
+		if notNull {
+			if err := json.Unmarshal(data, target.Addr().Interface()); err != nil {
+				return false, fmt.Errorf("scany: column: '%s': decode JSON into %v: %w", column, target.Type(), err)
+			}
+		}
+		if isNull {
+			value.Field(nullValidFieldIndex).SetBool(notNull)
+		}
+		return notNull, nil
+	}
+}
+
+// nullScanTarget returns a destination for Rows.Scan to fill the Null value natively:
+// the column is scanned into *T, where T is the Null value type.
+func nullScanTarget(nullValue reflect.Value) (interface{}, finishScanFunc) {
+	value := nullValue.Field(nullValueFieldIndex)
+	holder := reflect.New(reflect.PtrTo(value.Type()))
+	return holder.Interface(), func() (bool, error) {
+		valid := !holder.Elem().IsNil()
+		if valid {
+			value.Set(holder.Elem().Elem())
+		} else {
+			value.Set(reflect.Zero(value.Type()))
+		}
+		nullValue.Field(nullValidFieldIndex).SetBool(valid)
+		return valid, nil
+	}
+}
+
+// nullableScanTarget returns a destination for Rows.Scan that accepts NULL for a value of any type.
+// NULL resets the value to zero.
+func nullableScanTarget(value reflect.Value) (interface{}, finishScanFunc) {
+	holder := reflect.New(reflect.PtrTo(value.Type()))
+	return holder.Interface(), func() (bool, error) {
+		if holder.Elem().IsNil() {
+			value.Set(reflect.Zero(value.Type()))
+			return false, nil
+		}
+		value.Set(holder.Elem().Elem())
+		return true, nil
+	}
+}
+
+// getPresentFields returns fields that rows contain a column for, mapped to that column.
+// It returns an error if rows contain more than one alias of the same field.
+func (rs *RowScanner) getPresentFields() (map[*fieldMeta]string, error) {
+	present := make(map[*fieldMeta]string, len(rs.columns))
+	for i, column := range rs.columns {
+		field := rs.columnFields[i]
+		if field == nil {
+			continue
+		}
+		if other, ok := present[field]; ok {
+			return nil, fmt.Errorf("scany: rows contain more than one alias of the same field: '%s' and '%s'",
+				other, column)
+		}
+		present[field] = column
+	}
+	return present, nil
+}
+
+// getAbsentFields returns fields that rows don't contain any column for.
+func (rs *RowScanner) getAbsentFields(present map[*fieldMeta]string) []*fieldMeta {
+	var fields []*fieldMeta
+	seen := make(map[*fieldMeta]struct{}, len(rs.columnToFieldIndex))
+	for _, field := range rs.columnToFieldIndex {
+		if _, ok := seen[field]; ok {
+			// The field is mapped to several column aliases.
+			continue
+		}
+		seen[field] = struct{}{}
+		if _, ok := present[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+func (rs *RowScanner) getAbsentDefaultFields(present map[*fieldMeta]string) []*fieldMeta {
+	var fields []*fieldMeta
+	for _, field := range rs.getAbsentFields(present) {
+		if field.Default.IsValid() {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+func (rs *RowScanner) ensureRequiredColumns(present map[*fieldMeta]string) error {
+	if rs.sparseFields {
+		return nil
+	}
+	var missing []string
+	for _, field := range rs.getAbsentFields(present) {
+		if field.Required {
+			missing = append(missing, strings.Join(field.Columns, columnAliasSeparator))
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("scany: rows don't contain columns of required fields: %s", strings.Join(missing, ", "))
+	}
 	return nil
 }
 
-func (rs *RowScanner) ensureDistinctColumns() error {
+// ensureDistinctColumns checks that column names don't repeat,
+// columns bound to fields by ordinal are excluded from the check.
+func (rs *RowScanner) ensureDistinctColumns(positions map[int]*fieldMeta) error {
 	seen := make(map[string]struct{}, len(rs.columns))
-	for _, column := range rs.columns {
+	for i, column := range rs.columns {
+		if _, ok := positions[i]; ok {
+			continue
+		}
 		if _, ok := seen[column]; ok {
 			return fmt.Errorf("scany: rows contain a duplicate column '%s'", column)
 		}
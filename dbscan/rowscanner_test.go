@@ -3,6 +3,8 @@ package dbscan_test
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -619,6 +621,131 @@ func TestRowScanner_Scan_invalidStructDestination_returnsErr(t *testing.T) {
 	}
 }
 
+func TestRowScanner_Scan_defaultTagOption(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		query    string
+		expected interface{}
+	}{
+		{
+			name: "field is set to default value when column is absent",
+			query: `
+				SELECT 'foo val' AS foo
+			`,
+			expected: struct {
+				Foo     string
+				Bar     string        `db:"bar,default=bar default"`
+				Count   int           `db:"count,default=5"`
+				Name    *string       `db:"name,default=name default"`
+				Timeout time.Duration `db:"timeout,default=1m30s"`
+				Since   time.Time     `db:"since,default=2020-10-16"`
+			}{
+				Foo:     "foo val",
+				Bar:     "bar default",
+				Count:   5,
+				Name:    makeStrPtr("name default"),
+				Timeout: 90 * time.Second,
+				Since:   time.Date(2020, 10, 16, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "field is set to default value when column is NULL",
+			query: `
+				SELECT 'foo val' AS foo, NULL AS bar
+			`,
+			expected: struct {
+				Foo string `db:"foo,default=foo default"`
+				Bar string `db:"bar,default=bar default"`
+			}{
+				Foo: "foo val",
+				Bar: "bar default",
+			},
+		},
+		{
+			name: "nested field is set to default value",
+			query: `
+				SELECT 'foo val' AS foo
+			`,
+			expected: struct {
+				Foo    string
+				Nested *struct {
+					Bar float64 `db:"bar,default=1.5"`
+				}
+			}{
+				Foo: "foo val",
+				Nested: &struct {
+					Bar float64 `db:"bar,default=1.5"`
+				}{Bar: 1.5},
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rows := queryRows(t, tc.query)
+			dst := allocateDestination(tc.expected)
+			err := scan(t, dst, rows)
+			require.NoError(t, err)
+			assertDestinationEqual(t, tc.expected, dst)
+		})
+	}
+}
+
+func TestRowScanner_Scan_invalidDefaultTagOption_returnsErr(t *testing.T) {
+	t.Parallel()
+	rows := queryRows(t, singleRowsQuery)
+	dst := &struct {
+		Foo string
+		Bar string
+		Baz int `db:"baz,default=baz"`
+	}{}
+	expectedErr := "doing scan: starting: scany: map columns to fields of struct { Foo string; Bar string; " +
+		"Baz int \"db:\\\"baz,default=baz\\\"\" }: scany: field Baz: invalid default value: " +
+		"scany: parse \"baz\" into int: strconv.ParseInt: parsing \"baz\": invalid syntax"
+	err := scan(t, dst, rows)
+	assert.EqualError(t, err, expectedErr)
+}
+
+func TestRowScanner_Scan_fieldMapper(t *testing.T) {
+	t.Parallel()
+	type Audit struct {
+		CreatedBy string
+	}
+	type User struct {
+		ID    string `json:"user_id"`
+		Name  string `json:"-"`
+		Email string
+		Audit
+	}
+	mapper := dbscan.FieldMapperFunc(func(field reflect.StructField, _ reflect.Type, _ string) string {
+		if field.Anonymous {
+			return strings.ToLower(field.Name)
+		}
+		if jsonTag, ok := field.Tag.Lookup("json"); ok {
+			return strings.Split(jsonTag, ",")[0]
+		}
+		return dbscan.SnakeCaseMapper(field.Name)
+	})
+	api, err := getAPI(dbscan.WithFieldMapper(mapper))
+	require.NoError(t, err)
+	query := `
+		SELECT 'id val' AS user_id, 'email val' AS email, 'created_by val' AS "audit.created_by"
+	`
+	rows := queryRows(t, query)
+	defer rows.Close() //nolint: errcheck
+	rows.Next()
+
+	dst := &User{}
+	err = api.ScanRow(dst, rows)
+	require.NoError(t, err)
+	requireNoRowsErrorsAndClose(t, rows)
+
+	expected := &User{ID: "id val", Email: "email val", Audit: Audit{CreatedBy: "created_by val"}}
+	assert.Equal(t, expected, dst)
+}
+
 func TestRowScanner_Scan_mapDestination(t *testing.T) {
 	t.Parallel()
 	cases := []struct {
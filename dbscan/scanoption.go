@@ -0,0 +1,61 @@
+package dbscan
+
+// ScanOption is a function type that changes the behavior of a single ScanAll call.
+// Unlike APIOption it doesn't affect other calls.
+type ScanOption func(cfg *scanConfig)
+
+type scanConfig struct {
+	keyColumn    string
+	uniqueKeys   bool
+	captures     []capture
+	sparseFields bool
+}
+
+// capture is a column that is scanned into a separate destination, see WithCapture.
+type capture struct {
+	column string
+	dst    interface{}
+}
+
+func newScanConfig(opts []ScanOption) *scanConfig {
+	cfg := &scanConfig{}
+	for _, o := range opts {
+		o(cfg)
+	}
+	return cfg
+}
+
+// WithKeyColumn sets the column that map keys are taken from when ScanAll scans rows into a map,
+// see "Scanning into keyed maps" section in the package doc.
+func WithKeyColumn(column string) ScanOption {
+	return func(cfg *scanConfig) {
+		cfg.keyColumn = column
+	}
+}
+
+// WithUniqueKeys makes ScanAll return an error if several rows have the same map key,
+// by default the last row wins.
+// It can't be used with maps that collect rows with the same key into slice values.
+func WithUniqueKeys(enabled bool) ScanOption {
+	return func(cfg *scanConfig) {
+		cfg.uniqueKeys = enabled
+	}
+}
+
+// WithCapture makes ScanAll scan the column of the first row into dst, which must be a non nil pointer.
+// The column is excluded from the element mapping, so the element type doesn't need a field for it.
+// It's handy for values that are the same in every row, like "COUNT(*) OVER () AS total_count" of paginated queries.
+// If there are no rows, dst isn't modified.
+func WithCapture(column string, dst interface{}) ScanOption {
+	return func(cfg *scanConfig) {
+		cfg.captures = append(cfg.captures, capture{column: column, dst: dst})
+	}
+}
+
+// WithSparseFields makes ScanAll and ScanOne accept rows without columns of fields with "required" tag option,
+// so rows that select only some fields, see API.Columns, can be scanned into the full struct type.
+func WithSparseFields(enabled bool) ScanOption {
+	return func(cfg *scanConfig) {
+		cfg.sparseFields = enabled
+	}
+}
@@ -0,0 +1,205 @@
+package dbscan
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Columns is a package-level helper function that uses the DefaultAPI object.
+// See API.Columns for details.
+func Columns(example interface{}, fieldPaths ...string) ([]string, error) {
+	return DefaultAPI.Columns(example, fieldPaths...)
+}
+
+// Columns returns the columns that the fields of a struct type are mapped to,
+// it's handy to build the SELECT list for sparse fieldsets, like ?fields= parameters of an API.
+//
+// example is a value of the struct type, a pointer to it or a slice of them.
+// fieldPaths are Go field names relative to the struct type, nested fields are separated by ".",
+// for example "Address.City". Fields promoted from embedded structs can be referenced directly.
+// A path to a nested struct selects all its columns, with the nested prefixes.
+// Columns are returned in the order of the field paths, without duplicates.
+// If no field paths are provided, Columns returns columns of all fields in the struct field order.
+//
+// Columns returns column names as they appear in rows, nested columns contain the separator, like "address.city".
+// Use QuoteColumns to put them into a query.
+//
+// Columns uses the same mapping as scanning, so the result can be scanned into the struct type.
+// The other fields keep their zero or default values, see WithSparseFields for fields with "required" tag option.
+// Columns of RowUnmarshaler fields, fields with "remain" tag option and column patterns depend on the rows,
+// so Columns returns an error if such a field is selected, or if no field paths are provided and the struct has one.
+func (api *API) Columns(example interface{}, fieldPaths ...string) ([]string, error) {
+	structType := reflect.TypeOf(example)
+	for structType != nil && (structType.Kind() == reflect.Ptr || structType.Kind() == reflect.Slice) {
+		structType = structType.Elem()
+	}
+	if structType == nil || !api.isStructValue(structType) {
+		return nil, fmt.Errorf("scany: columns can be listed only for a struct type, got %T", example)
+	}
+	mapping, err := api.getStructMapping(structType)
+	if err != nil {
+		return nil, fmt.Errorf("scany: map columns to fields of %v: %w", structType, err)
+	}
+	fields := columnFields(mapping)
+	dynamic := dynamicFields(mapping)
+	if len(fieldPaths) == 0 {
+		if len(dynamic) > 0 {
+			return nil, fmt.Errorf("scany: list columns of %v: %s, select field paths explicitly",
+				structType, dynamic[0].describe(structType))
+		}
+		columns := make([]string, len(fields))
+		for i, field := range fields {
+			columns[i] = field.Columns[0]
+		}
+		return columns, nil
+	}
+	return pathColumns(structType, fields, dynamic, fieldPaths)
+}
+
+// QuoteColumns returns the columns as SQL identifiers in double quotes, double quotes inside are doubled.
+// A nested column, like "address.city", is quoted as a whole, so it refers to the column with that name
+// of a view or a subquery. For joined tables alias the source column instead: a.city AS "address.city".
+// Note that MySQL accepts double quoted identifiers only with ANSI_QUOTES SQL mode.
+func QuoteColumns(columns []string) []string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = `"` + strings.ReplaceAll(column, `"`, `""`) + `"`
+	}
+	return quoted
+}
+
+// pathColumns returns columns of the fields at the paths.
+func pathColumns(structType reflect.Type, fields []*fieldMeta, dynamic []*dynamicField, fieldPaths []string,
+) ([]string, error) {
+	var columns []string
+	selected := make(map[*fieldMeta]struct{}, len(fields))
+	for _, path := range fieldPaths {
+		index, err := fieldPathIndex(structType, path)
+		if err != nil {
+			return nil, fmt.Errorf("scany: list columns of %v: %w", structType, err)
+		}
+		for _, df := range dynamic {
+			if hasIndexPrefix(df.index, index) || hasIndexPrefix(index, df.index) {
+				return nil, fmt.Errorf("scany: list columns of %v: field %q: %s",
+					structType, path, df.describe(structType))
+			}
+		}
+		found := false
+		for _, field := range fields {
+			if !hasIndexPrefix(field.Index, index) {
+				continue
+			}
+			found = true
+			if _, ok := selected[field]; !ok {
+				selected[field] = struct{}{}
+				columns = append(columns, field.Columns[0])
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("scany: list columns of %v: field %q isn't mapped to any column", structType, path)
+		}
+	}
+	return columns, nil
+}
+
+// dynamicField is a field whose columns depend on the rows, so they can't be listed.
+type dynamicField struct {
+	index []int
+	kind  string
+}
+
+func (df *dynamicField) describe(structType reflect.Type) string {
+	return fmt.Sprintf("columns of %s field %s depend on rows and can't be listed",
+		df.kind, fieldPath(structType, df.index))
+}
+
+// dynamicFields returns RowUnmarshaler fields, the remain field and wildcard fields, sorted by their index.
+func dynamicFields(mapping *structMapping) []*dynamicField {
+	var fields []*dynamicField
+	seen := make(map[*fieldMeta]struct{})
+	for _, field := range mapping.columns {
+		if _, ok := seen[field]; !ok && field.RowUnmarshaler {
+			seen[field] = struct{}{}
+			fields = append(fields, &dynamicField{index: field.Index, kind: "RowUnmarshaler"})
+		}
+	}
+	if mapping.remain != nil {
+		fields = append(fields, &dynamicField{index: mapping.remain.Index, kind: fmt.Sprintf("%q", TagOptionRemain)})
+	}
+	for _, wf := range mapping.wildcards {
+		fields = append(fields, &dynamicField{index: wf.meta.Index, kind: "column pattern"})
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return lessIndex(fields[i].index, fields[j].index)
+	})
+	return fields
+}
+
+// columnFields returns fields that hold column values, sorted by their index.
+// Nested structs are left out, since their own fields are listed instead, as well as RowUnmarshaler fields.
+func columnFields(mapping *structMapping) []*fieldMeta {
+	seen := make(map[*fieldMeta]struct{}, len(mapping.columns))
+	all := make([]*fieldMeta, 0, len(mapping.columns))
+	for _, field := range mapping.columns {
+		if _, ok := seen[field]; !ok && !field.RowUnmarshaler {
+			seen[field] = struct{}{}
+			all = append(all, field)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return lessIndex(all[i].Index, all[j].Index)
+	})
+	fields := all[:0]
+	for i, field := range all {
+		// Fields of a nested struct follow the nested struct field itself.
+		if i+1 < len(all) && hasIndexPrefix(all[i+1].Index, field.Index) {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// fieldPathIndex returns the index sequence of the field at the path, see reflect.Value.FieldByIndex.
+func fieldPathIndex(structType reflect.Type, path string) ([]int, error) {
+	t := structType
+	var index []int
+	parts := strings.Split(path, ".")
+	for i, name := range parts {
+		if i > 0 {
+			if isNullType(t) {
+				index = append(index, nullValueFieldIndex)
+				t = t.Field(nullValueFieldIndex).Type
+			}
+			if t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t.Kind() != reflect.Struct {
+				return nil, fmt.Errorf("field %q: %s isn't a struct", path, strings.Join(parts[:i], "."))
+			}
+		}
+		field, ok := t.FieldByName(name)
+		if !ok {
+			return nil, fmt.Errorf("field %q: no such field", path)
+		}
+		index = append(index, field.Index...)
+		t = field.Type
+	}
+	return index, nil
+}
+
+// lessIndex reports whether the field with index a goes before the field with index b in the struct.
+func lessIndex(a, b []int) bool {
+	for k := 0; k < len(a) && k < len(b); k++ {
+		if a[k] != b[k] {
+			return a[k] < b[k]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func hasIndexPrefix(index, prefix []int) bool {
+	return len(index) >= len(prefix) && equalIndexes(index[:len(prefix)], prefix)
+}
@@ -0,0 +1,224 @@
+package dbscan_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/georgysavva/scany/v2/dbscan"
+)
+
+type sparseBase struct {
+	ID int64 `db:"id,required"`
+}
+
+type sparseAddress struct {
+	City   string
+	Street string `db:"street_name"`
+}
+
+type sparseUser struct {
+	sparseBase
+	Name     string `db:"name,required"`
+	Email    string `db:"email|mail"`
+	Address  sparseAddress
+	Billing  *sparseAddress         `db:"billing"`
+	Settings map[string]interface{} `db:"settings,json"`
+	Ignored  string                 `db:"-"`
+}
+
+func TestAPI_Columns(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name       string
+		example    interface{}
+		fieldPaths []string
+		expected   []string
+	}{
+		{
+			name:    "all fields",
+			example: sparseUser{},
+			expected: []string{
+				"id", "name", "email", "address.city", "address.street_name", "billing.city", "billing.street_name",
+				"settings",
+			},
+		},
+		{
+			name:       "selected fields in the path order",
+			example:    &sparseUser{},
+			fieldPaths: []string{"Email", "ID", "Address.City"},
+			expected:   []string{"email", "id", "address.city"},
+		},
+		{
+			name:       "nested struct selects all its columns",
+			example:    []*sparseUser{},
+			fieldPaths: []string{"Billing", "Name", "Billing.City"},
+			expected:   []string{"billing.city", "billing.street_name", "name"},
+		},
+		{
+			name:       "embedded struct path",
+			example:    sparseUser{},
+			fieldPaths: []string{"sparseBase.ID", "Settings"},
+			expected:   []string{"id", "settings"},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := testAPI.Columns(tc.example, tc.fieldPaths...)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestAPI_Columns_returnsErr(t *testing.T) {
+	t.Parallel()
+	type withDynamicFields struct {
+		Name  string
+		Price testMoney
+		Attrs map[string]string      `db:"attr_*"`
+		Extra map[string]interface{} `db:",remain"`
+	}
+	cases := []struct {
+		name        string
+		example     interface{}
+		fieldPaths  []string
+		expectedErr string
+	}{
+		{
+			name:        "unknown field",
+			example:     sparseUser{},
+			fieldPaths:  []string{"Phone"},
+			expectedErr: `scany: list columns of dbscan_test.sparseUser: field "Phone": no such field`,
+		},
+		{
+			name:       "path through non struct",
+			example:    sparseUser{},
+			fieldPaths: []string{"Name.First"},
+			expectedErr: `scany: list columns of dbscan_test.sparseUser: ` +
+				`field "Name.First": Name isn't a struct`,
+		},
+		{
+			name:        "ignored field",
+			example:     sparseUser{},
+			fieldPaths:  []string{"Ignored"},
+			expectedErr: `scany: list columns of dbscan_test.sparseUser: field "Ignored" isn't mapped to any column`,
+		},
+		{
+			name:       "RowUnmarshaler field",
+			example:    withDynamicFields{},
+			fieldPaths: []string{"Name", "Price"},
+			expectedErr: `scany: list columns of dbscan_test.withDynamicFields: field "Price": ` +
+				"columns of RowUnmarshaler field Price depend on rows and can't be listed",
+		},
+		{
+			name:       "column pattern field",
+			example:    withDynamicFields{},
+			fieldPaths: []string{"Attrs"},
+			expectedErr: `scany: list columns of dbscan_test.withDynamicFields: field "Attrs": ` +
+				"columns of column pattern field Attrs depend on rows and can't be listed",
+		},
+		{
+			name:       "remain field",
+			example:    withDynamicFields{},
+			fieldPaths: []string{"Extra"},
+			expectedErr: `scany: list columns of dbscan_test.withDynamicFields: field "Extra": ` +
+				`columns of "remain" field Extra depend on rows and can't be listed`,
+		},
+		{
+			name:    "all fields of struct with dynamic fields",
+			example: withDynamicFields{},
+			expectedErr: "scany: list columns of dbscan_test.withDynamicFields: " +
+				"columns of RowUnmarshaler field Price depend on rows and can't be listed, select field paths explicitly",
+		},
+		{
+			name:        "non struct type",
+			example:     "foo",
+			expectedErr: "scany: columns can be listed only for a struct type, got string",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := testAPI.Columns(tc.example, tc.fieldPaths...)
+			assert.EqualError(t, err, tc.expectedErr)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestScanAll_withSparseFields(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT *
+		FROM (
+			VALUES ('foo val', 'bar val'), ('foo val 2', 'bar val 2')
+		) AS t (email, "address.city")
+	`
+	expected := []sparseUser{
+		{Email: "foo val", Address: sparseAddress{City: "bar val"}},
+		{Email: "foo val 2", Address: sparseAddress{City: "bar val 2"}},
+	}
+
+	var got []sparseUser
+	err := testAPI.ScanAll(&got, queryRows(t, query), dbscan.WithSparseFields(true))
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
+
+func TestScanAll_withSparseFields_quotedNestedColumns(t *testing.T) {
+	t.Parallel()
+	columns, err := testAPI.Columns(sparseUser{}, "Email", "Address.City")
+	require.NoError(t, err)
+	query := "SELECT " + strings.Join(dbscan.QuoteColumns(columns), ", ") + `
+		FROM (
+			VALUES ('foo val', 'bar val', 'baz val')
+		) AS t (email, "address.city", "address.street_name")
+	`
+	expected := []sparseUser{
+		{Email: "foo val", Address: sparseAddress{City: "bar val"}},
+	}
+
+	var got []sparseUser
+	err = testAPI.ScanAll(&got, queryRows(t, query), dbscan.WithSparseFields(true))
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
+
+func TestQuoteColumns(t *testing.T) {
+	t.Parallel()
+	got := dbscan.QuoteColumns([]string{"name", "address.city", `say "hi"`})
+	assert.Equal(t, []string{`"name"`, `"address.city"`, `"say ""hi"""`}, got)
+}
+
+func TestScanOne_withSparseFields(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT 'foo val' AS email, 'bar val' AS "billing.city"
+	`
+	expected := &sparseUser{Email: "foo val", Billing: &sparseAddress{City: "bar val"}}
+
+	got := &sparseUser{}
+	err := testAPI.ScanOne(got, queryRows(t, query), dbscan.WithSparseFields(true))
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
+
+func TestScanAll_withoutSparseFields_requiredColumnIsMissing_returnsErr(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT 'foo val' AS email
+	`
+	var got []sparseUser
+	err := testAPI.ScanAll(&got, queryRows(t, query))
+	assert.EqualError(t, err, "scanning: scanning: doing scan: starting: "+
+		"scany: rows don't contain columns of required fields: id, name")
+}
@@ -1,6 +1,7 @@
 package dbscan
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
 )
@@ -9,71 +10,328 @@ type toTraverse struct {
 	Type         reflect.Type
 	IndexPrefix  []int
 	ColumnPrefix string
+	// RawPrefix is prepended to the column names of the struct fields as is, see TagOptionPrefix.
+	RawPrefix   string
+	NullIndexes [][]int
+	Mappings    []*activeMapping
 }
 
-func (api *API) getColumnToFieldIndexMap(structType reflect.Type) map[string][]int {
-	result := make(map[string][]int, structType.NumField())
+// column returns the column name for a field of the struct being traversed.
+func (t *toTraverse) column(api *API, columnPart string) string {
+	return api.buildColumn(t.ColumnPrefix, t.RawPrefix+columnPart)
+}
+
+// columnPrefix returns the beginning of column names of the struct fields, it's empty for the root struct.
+func (t *toTraverse) columnPrefix(api *API) string {
+	prefix := t.column(api, "")
+	if t.RawPrefix == "" && prefix != "" {
+		prefix += api.columnSeparator
+	}
+	return prefix
+}
+
+// nested returns the traversal of the struct nested into the field with the column part.
+func (t *toTraverse) nested(api *API, structType reflect.Type, index []int, field reflect.StructField,
+	columnPart string, tag Tag,
+) *toTraverse {
+	nested := &toTraverse{
+		Type:         structType,
+		IndexPrefix:  index,
+		ColumnPrefix: t.ColumnPrefix,
+		RawPrefix:    t.RawPrefix,
+		NullIndexes:  t.NullIndexes,
+		Mappings:     api.nestedMappings(t.Mappings, field.Name, structType),
+	}
+	switch prefix, hasPrefix := tag.Option(TagOptionPrefix); {
+	case hasPrefix:
+		nested.RawPrefix += prefix
+	case tag.HasOption(TagOptionInline) || columnPart == "":
+		// Columns of the nested struct are propagated as is.
+	default:
+		nested.ColumnPrefix = t.column(api, columnPart)
+		nested.RawPrefix = ""
+	}
+	return nested
+}
+
+// fieldMeta describes a struct field that a column is mapped to.
+type fieldMeta struct {
+	// Index is the index sequence of the field, see reflect.Value.FieldByIndex.
+	Index []int
+	// Columns contains all columns the field is mapped to: the column name and its aliases.
+	Columns []string
+	// NullIndexes contains indexes of all Null fields that enclose this field, from the outermost to the innermost.
+	// If the column isn't NULL, all of them become valid.
+	NullIndexes [][]int
+	// Default is the value set to the field when its column is absent from rows or is NULL.
+	// It's invalid if the field doesn't have a default value.
+	Default reflect.Value
+	// RowUnmarshaler is true if the field implements RowUnmarshaler,
+	// in that case it receives all columns under its column prefix.
+	RowUnmarshaler bool
+	// Required is true if rows must contain the field column, see TagOptionRequired.
+	Required bool
+	// JSON is true if the column value is decoded as JSON into the field, see TagOptionJSON.
+	JSON bool
+	// NullZero is true if NULL sets the field to zero, see TagOptionNullZero.
+	NullZero bool
+	// PrimaryKey is true if the field value is the map key for keyed map destinations, see TagOptionPK.
+	PrimaryKey bool
+	// TreeRole is the value of the tree tag option, see TagOptionTree.
+	TreeRole string
+}
+
+// structMapping describes how columns are mapped to fields of a struct type.
+type structMapping struct {
+	// columns maps column names to struct fields.
+	columns map[string]*fieldMeta
+	// remain is the field that collects columns without a corresponding field, see TagOptionRemain.
+	remain *remainField
+	// wildcards contains map and slice fields that collect columns by a name pattern.
+	wildcards []*wildcardField
+	// positions maps column ordinals to struct fields, see Positional.
+	positions map[int]*fieldMeta
+	// positional is true if all fields are bound to columns by ordinal.
+	positional bool
+	// treeChildren is the field that receives child nodes in ScanTree, see TagOptionTree.
+	treeChildren *fieldMeta
+	// structs contains nested structs whose fields are mapped to columns, except embedded ones.
+	// Index of a struct inside Null ends with the Null value field index.
+	structs []*nestedStruct
+}
+
+// nestedStruct is a nested struct field traversed by the mapping.
+type nestedStruct struct {
+	Index []int
+	Type  reflect.Type
+}
+
+func (api *API) getStructMapping(structType reflect.Type) (*structMapping, error) {
+	if isPositionalStruct(structType) {
+		return api.getPositionalMapping(structType)
+	}
+	result := &structMapping{columns: make(map[string]*fieldMeta, structType.NumField())}
 	var queue []*toTraverse
-	queue = append(queue, &toTraverse{Type: structType, IndexPrefix: nil, ColumnPrefix: ""})
+	queue = append(queue, &toTraverse{
+		Type:         structType,
+		IndexPrefix:  nil,
+		ColumnPrefix: "",
+		Mappings:     api.nestedMappings(nil, "", structType),
+	})
 	for len(queue) > 0 {
 		traversal := queue[0]
 		queue = queue[1:]
-		structType := traversal.Type
-		for i := 0; i < structType.NumField(); i++ {
-			field := structType.Field(i)
-
-			if field.PkgPath != "" && !field.Anonymous {
-				// Field is unexported, skip it.
-				continue
+		for i := 0; i < traversal.Type.NumField(); i++ {
+			field := traversal.Type.Field(i)
+			nested, err := api.mapField(traversal, field, result)
+			if err != nil {
+				return nil, err
 			}
-
-			dbTag, dbTagPresent := field.Tag.Lookup(api.structTagKey)
-			if dbTagPresent {
-				dbTag = strings.Split(dbTag, ",")[0]
-			}
-			if dbTag == "-" {
-				// Field is ignored, skip it.
-				continue
+			if nested != nil {
+				queue = append(queue, nested)
+				if !field.Anonymous {
+					result.structs = append(result.structs, &nestedStruct{Index: nested.IndexPrefix, Type: nested.Type})
+				}
 			}
+		}
+	}
 
-			index := make([]int, 0, len(traversal.IndexPrefix)+len(field.Index))
-			index = append(index, traversal.IndexPrefix...)
-			index = append(index, field.Index...)
+	return result, nil
+}
 
-			columnPart := dbTag
-			if !dbTagPresent {
-				columnPart = api.fieldMapperFn(field.Name)
-			}
-			if !field.Anonymous {
-				column := api.buildColumn(traversal.ColumnPrefix, columnPart)
+// mapField adds the column of the field to the result.
+// If the field is a struct that should be traversed as well, it returns its traversal.
+func (api *API) mapField(traversal *toTraverse, field reflect.StructField, result *structMapping,
+) (*toTraverse, error) {
+	if field.PkgPath != "" && !field.Anonymous {
+		// Field is unexported, skip it.
+		return nil, nil
+	}
 
-				if _, exists := result[column]; !exists {
-					result[column] = index
-				}
-			}
+	tag, tagPresent := api.fieldTag(traversal, field)
+	if tag.Ignored() {
+		// Field is ignored, skip it.
+		return nil, nil
+	}
+
+	index := make([]int, 0, len(traversal.IndexPrefix)+len(field.Index))
+	index = append(index, traversal.IndexPrefix...)
+	index = append(index, field.Index...)
+
+	if tag.HasOption(TagOptionRemain) {
+		return nil, result.setRemain(field, index, traversal.columnPrefix(api))
+	}
+	if role, _ := tag.Option(TagOptionTree); role == TreeRoleChildren {
+		return nil, result.setTreeChildren(field, index)
+	}
+
+	columnPart := tag.Name
+	// An empty tag name keeps the default column name, unless it removes the prefix of a nested struct.
+	if !tagPresent || (tag.Name == "" && isColumnType(field.Type)) {
+		// For embedded structs the mapped name is the prefix of their columns.
+		columnPart = api.fieldMapper.MapField(field, traversal.Type, traversal.ColumnPrefix)
+		if columnPart == "-" {
+			return nil, nil
+		}
+	}
+	aliases := strings.Split(columnPart, columnAliasSeparator)
+	// Only the first alias is used as the prefix for columns of nested structs.
+	columnPart = aliases[0]
+	if position, ok := parsePosition(columnPart); ok && tagPresent && !field.Anonymous {
+		meta, err := api.newFieldMeta(field, index, traversal.NullIndexes, tag)
+		if err != nil {
+			return nil, err
+		}
+		return nil, result.addPosition(field, position, meta)
+	}
+	if isWildcardColumn(columnPart) {
+		return nil, result.addWildcard(field, index, traversal.column(api, columnPart))
+	}
+	if implementsRowUnmarshaler(field.Type) {
+		// The field decodes its columns by itself, so it isn't traversed.
+		return nil, api.mapRowUnmarshaler(traversal, field, index, columnPart, result)
+	}
 
-			childType := field.Type
-			if field.Type.Kind() == reflect.Ptr {
-				childType = field.Type.Elem()
+	if !field.Anonymous {
+		var meta *fieldMeta
+		for _, alias := range aliases {
+			column := traversal.column(api, alias)
+			if _, exists := result.columns[column]; exists {
+				continue
 			}
-			if childType.Kind() == reflect.Struct {
-				if field.Anonymous {
-					// If "db" tag is present for embedded struct
-					// use it with "." to prefix all column from the embedded struct.
-					// the default behavior is to propagate columns as is.
-					columnPart = dbTag
+			if meta == nil {
+				var err error
+				meta, err = api.newFieldMeta(field, index, traversal.NullIndexes, tag)
+				if err != nil {
+					return nil, err
 				}
-				columnPrefix := api.buildColumn(traversal.ColumnPrefix, columnPart)
-				queue = append(queue, &toTraverse{
-					Type:         childType,
-					IndexPrefix:  index,
-					ColumnPrefix: columnPrefix,
-				})
 			}
+			meta.Columns = append(meta.Columns, column)
+			result.columns[column] = meta
+		}
+		if tag.HasOption(TagOptionJSON) {
+			// The whole column value is decoded into the field, so its fields aren't mapped to columns.
+			return nil, nil
 		}
 	}
 
-	return result
+	childType := field.Type
+	isNull := isNullType(childType)
+	if isNull {
+		// Null isn't traversed as a regular struct,
+		// instead, if it wraps a struct, its value fields are mapped under the Null field prefix.
+		childType = childType.Field(nullValueFieldIndex).Type
+	}
+	if childType.Kind() == reflect.Ptr {
+		childType = childType.Elem()
+	}
+	if childType.Kind() != reflect.Struct || (isNull && field.Anonymous) {
+		if tag.HasOption(TagOptionInline) || tag.HasOption(TagOptionPrefix) {
+			return nil, fmt.Errorf("scany: field %s: %q and %q tag options are allowed only for struct fields",
+				field.Name, TagOptionInline, TagOptionPrefix)
+		}
+		return nil, nil
+	}
+
+	// For embedded structs the prefix is empty unless it's set by the "db" tag or the field mapper,
+	// so the default behavior is to propagate columns as is.
+	nested := traversal.nested(api, childType, index, field, columnPart, tag)
+	if isNull {
+		nested.IndexPrefix = append(index[:len(index):len(index)], nullValueFieldIndex)
+		nullIndexes := make([][]int, 0, len(traversal.NullIndexes)+1)
+		nullIndexes = append(nullIndexes, traversal.NullIndexes...)
+		nested.NullIndexes = append(nullIndexes, index)
+	}
+	return nested, nil
+}
+
+// mapRowUnmarshaler registers the RowUnmarshaler field under its column prefix,
+// see RowScanner.getUnmarshalerFields.
+func (api *API) mapRowUnmarshaler(traversal *toTraverse, field reflect.StructField, index []int, columnPart string,
+	result *structMapping,
+) error {
+	if field.PkgPath != "" {
+		// dbscan can't call UnmarshalRow of an unexported embedded field.
+		return fmt.Errorf("scany: field %s: embedded RowUnmarshaler must be exported", field.Name)
+	}
+	column := traversal.column(api, columnPart)
+	if column == "" {
+		// Without a prefix the field would receive no columns, and its columns would be reported as unknown.
+		return fmt.Errorf("scany: field %s: embedded RowUnmarshaler requires a column prefix in the struct tag",
+			field.Name)
+	}
+	if _, exists := result.columns[column]; !exists {
+		result.columns[column] = &fieldMeta{
+			Index:          index,
+			Columns:        []string{column},
+			NullIndexes:    traversal.NullIndexes,
+			RowUnmarshaler: true,
+		}
+	}
+	return nil
+}
+
+// fieldTag returns the tag of the field, the registered mapping takes precedence over struct tags.
+func (api *API) fieldTag(traversal *toTraverse, field reflect.StructField) (Tag, bool) {
+	if value, ok := lookupMapping(traversal.Mappings, field.Name); ok {
+		return ParseTag(value), true
+	}
+	return api.LookupTag(field)
+}
+
+// isColumnType reports whether the field type holds a single column value rather than being a nested struct.
+func isColumnType(t reflect.Type) bool {
+	if isNullType(t) {
+		t = t.Field(nullValueFieldIndex).Type
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() != reflect.Struct || t == timeType
+}
+
+func (api *API) newFieldMeta(field reflect.StructField, index []int, nullIndexes [][]int, tag Tag,
+) (*fieldMeta, error) {
+	meta := &fieldMeta{
+		Index:       index,
+		NullIndexes: nullIndexes,
+		Required:    tag.HasOption(TagOptionRequired),
+		JSON:        tag.HasOption(TagOptionJSON),
+		NullZero:    tag.HasOption(TagOptionNullZero),
+		PrimaryKey:  tag.HasOption(TagOptionPK),
+	}
+	meta.TreeRole, _ = tag.Option(TagOptionTree)
+	if defaultValue, ok := tag.Option(TagOptionDefault); ok {
+		var err error
+		meta.Default, err = api.parseDefaultValue(field.Type, defaultValue)
+		if err != nil {
+			return nil, fmt.Errorf("scany: field %s: invalid default value: %w", field.Name, err)
+		}
+	}
+	return meta, nil
+}
+
+// parseDefaultValue parses the default value from the struct tag according to the field type.
+// Enum values are parsed from their database names.
+func (api *API) parseDefaultValue(fieldType reflect.Type, s string) (reflect.Value, error) {
+	v := reflect.New(fieldType).Elem()
+	enumValue := v
+	if fieldType.Kind() == reflect.Ptr {
+		enumValue = reflect.New(fieldType.Elem()).Elem()
+	}
+	if parse := api.getEnumParser(enumValue.Type()); parse != nil {
+		if !parse(enumValue, s) {
+			return reflect.Value{}, fmt.Errorf("scany: unknown value %q for enum type %v", s, enumValue.Type())
+		}
+		if fieldType.Kind() == reflect.Ptr {
+			v.Set(enumValue.Addr())
+		}
+		return v, nil
+	}
+	if err := assignString(v, s); err != nil {
+		return reflect.Value{}, err
+	}
+	return v, nil
 }
 
 func (api *API) buildColumn(parts ...string) string {
@@ -0,0 +1,113 @@
+package dbscan
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Struct tag options supported by dbscan.
+const (
+	// TagOptionRequired makes dbscan return an error if rows don't contain the field column.
+	TagOptionRequired = "required"
+	// TagOptionJSON makes dbscan decode the column value as JSON into the field.
+	TagOptionJSON = "json"
+	// TagOptionNullZero allows the column to be NULL for a field of a non-nullable type,
+	// NULL sets the field to its zero value.
+	TagOptionNullZero = "nullzero"
+	// TagOptionDefault sets the value for the field when its column is absent from rows or is NULL,
+	// for example `db:"status,default=active"`.
+	TagOptionDefault = "default"
+	// TagOptionInline maps fields of a nested struct to columns without the prefix,
+	// the same way as for embedded structs.
+	TagOptionInline = "inline"
+	// TagOptionPrefix sets the prefix for columns of a nested struct,
+	// it's prepended to the column names as is, without the column separator,
+	// for example `db:",prefix=billing_"` maps the nested "city" field to "billing_city" column.
+	TagOptionPrefix = "prefix"
+	// TagOptionRemain marks a map field that collects all columns without a corresponding struct field,
+	// for example `db:",remain"`. The map key type must be string,
+	// column values are scanned into the map element type.
+	// In a nested struct it collects only columns with the nested struct prefix, the keys are without the prefix.
+	TagOptionRemain = "remain"
+	// TagOptionPK marks the field whose value is the map key when ScanAll scans rows into a map,
+	// see "Scanning into keyed maps" section in the package doc.
+	TagOptionPK = "pk"
+	// TagOptionTree marks the fields that ScanTree links nodes by, for example `db:"parent_id,tree=parent"`,
+	// see TreeRoleID, TreeRoleParent and TreeRoleChildren.
+	TagOptionTree = "tree"
+)
+
+// Values of the tree tag option.
+const (
+	// TreeRoleID marks the field with the node id.
+	TreeRoleID = "id"
+	// TreeRoleParent marks the field with the parent node id, NULL or zero parent makes the node a root.
+	TreeRoleParent = "parent"
+	// TreeRoleChildren marks the []*T field that receives child nodes, it isn't mapped to any column.
+	TreeRoleChildren = "children"
+)
+
+// columnAliasSeparator separates column aliases in the column name, for example `db:"owner_id|user_id"`.
+const columnAliasSeparator = "|"
+
+// Tag is a parsed dbscan struct tag value.
+// The tag value consists of the column name and options separated by ",",
+// an option is either a single key, like "required", or a key with a value, like "default=active".
+// Option values can't contain commas. Unknown options are ignored,
+// that makes dbscan compatible with the struct tag formats of other libraries.
+type Tag struct {
+	// Name is the column name or "-" if the field is ignored.
+	// It can contain several column aliases separated by "|", see Aliases.
+	Name string
+	// Options contains tag options by keys, options without a value are mapped to the empty string.
+	Options map[string]string
+}
+
+// ParseTag parses the struct tag value.
+func ParseTag(value string) Tag {
+	parts := strings.Split(value, ",")
+	tag := Tag{Name: parts[0], Options: make(map[string]string, len(parts)-1)}
+	for _, option := range parts[1:] {
+		key, optionValue := option, ""
+		if i := strings.Index(option, "="); i >= 0 {
+			key, optionValue = option[:i], option[i+1:]
+		}
+		tag.Options[key] = optionValue
+	}
+	return tag
+}
+
+// Ignored reports whether the field is excluded from scanning by the "-" name.
+func (t Tag) Ignored() bool {
+	return t.Name == "-"
+}
+
+// Aliases returns all column names from the tag name separated by "|".
+func (t Tag) Aliases() []string {
+	return strings.Split(t.Name, columnAliasSeparator)
+}
+
+// HasOption reports whether the tag contains the option.
+func (t Tag) HasOption(key string) bool {
+	_, ok := t.Options[key]
+	return ok
+}
+
+// Option returns the option value and reports whether the tag contains the option.
+func (t Tag) Option(key string) (string, bool) {
+	value, ok := t.Options[key]
+	return value, ok
+}
+
+// LookupTag returns the parsed tag of the struct field the same way dbscan reads it:
+// struct tag keys are consulted in the order they were passed to WithStructTagKeys,
+// the first one present is used. It reports false if the field has none of the keys.
+// Mappings registered with RegisterMapping aren't taken into account.
+func (api *API) LookupTag(field reflect.StructField) (Tag, bool) {
+	for _, key := range api.structTagKeys {
+		if value, ok := field.Tag.Lookup(key); ok {
+			return ParseTag(value), true
+		}
+	}
+	return Tag{}, false
+}
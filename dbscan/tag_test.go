@@ -0,0 +1,354 @@
+package dbscan_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/georgysavva/scany/v2/dbscan"
+)
+
+func TestParseTag(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		value    string
+		expected dbscan.Tag
+	}{
+		{
+			name:     "name only",
+			value:    "foo",
+			expected: dbscan.Tag{Name: "foo", Options: map[string]string{}},
+		},
+		{
+			name:  "name with options",
+			value: "foo,required,default=bar",
+			expected: dbscan.Tag{Name: "foo", Options: map[string]string{
+				dbscan.TagOptionRequired: "",
+				dbscan.TagOptionDefault:  "bar",
+			}},
+		},
+		{
+			name:     "aliases",
+			value:    "foo|bar,required",
+			expected: dbscan.Tag{Name: "foo|bar", Options: map[string]string{dbscan.TagOptionRequired: ""}},
+		},
+		{
+			name:     "options without name",
+			value:    ",prefix=foo_",
+			expected: dbscan.Tag{Name: "", Options: map[string]string{dbscan.TagOptionPrefix: "foo_"}},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := dbscan.ParseTag(tc.value)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestAPI_LookupTag(t *testing.T) {
+	t.Parallel()
+	api, err := getAPI(dbscan.WithStructTagKeys("db", "json"))
+	require.NoError(t, err)
+	type User struct {
+		ID    string `db:"id,required" json:"user_id"`
+		Email string `json:"mail,omitempty"`
+		Name  string
+	}
+	userType := reflect.TypeOf(User{})
+
+	tag, ok := api.LookupTag(userType.Field(0))
+	assert.True(t, ok)
+	assert.Equal(t, "id", tag.Name)
+	assert.True(t, tag.HasOption(dbscan.TagOptionRequired))
+
+	tag, ok = api.LookupTag(userType.Field(1))
+	assert.True(t, ok)
+	assert.Equal(t, "mail", tag.Name)
+
+	_, ok = api.LookupTag(userType.Field(2))
+	assert.False(t, ok)
+}
+
+func TestRowScanner_Scan_tagOptions(t *testing.T) {
+	t.Parallel()
+	type Address struct {
+		City string
+	}
+	cases := []struct {
+		name     string
+		opts     []dbscan.APIOption
+		query    string
+		expected interface{}
+	}{
+		{
+			name: "struct tag keys fallback",
+			opts: []dbscan.APIOption{dbscan.WithStructTagKeys("db", "sql", "json")},
+			query: `
+				SELECT 'foo val' AS foo, 'bar val' AS bar_column, 'baz val' AS baz_json
+			`,
+			expected: struct {
+				Foo string `db:"foo" json:"-"`
+				Bar string `sql:"bar_column" json:"bar_json"`
+				Baz string `json:"baz_json,omitempty"`
+				Qux string `json:"-"`
+			}{
+				Foo: "foo val",
+				Bar: "bar val",
+				Baz: "baz val",
+			},
+		},
+		{
+			name: "json option",
+			query: `
+				SELECT '{"key": 1}'::JSONB AS foo, NULL::JSONB AS bar, '[1, 2]'::JSONB AS baz
+			`,
+			expected: struct {
+				Foo map[string]int     `db:"foo,json"`
+				Bar *Address           `db:"bar,json"`
+				Baz dbscan.Null[[]int] `db:"baz,json"`
+			}{
+				Foo: map[string]int{"key": 1},
+				Bar: nil,
+				Baz: dbscan.NewNull([]int{1, 2}),
+			},
+		},
+		{
+			name: "nullzero option",
+			query: `
+				SELECT NULL::TEXT AS foo, NULL::INT AS bar
+			`,
+			expected: struct {
+				Foo string `db:",nullzero"`
+				Bar int    `db:",nullzero"`
+			}{
+				Foo: "",
+				Bar: 0,
+			},
+		},
+		{
+			name: "column alias",
+			query: `
+				SELECT 'foo val' AS foo_old, 'bar val' AS bar
+			`,
+			expected: struct {
+				Foo string `db:"foo|foo_old"`
+				Bar string `db:"bar|bar_old"`
+			}{
+				Foo: "foo val",
+				Bar: "bar val",
+			},
+		},
+		{
+			name: "remain option",
+			query: `
+				SELECT 'foo val' AS foo, 'bar val' AS bar, NULL::TEXT AS baz
+			`,
+			expected: struct {
+				Foo   string
+				Extra map[string]string `db:",remain"`
+			}{
+				Foo:   "foo val",
+				Extra: map[string]string{"bar": "bar val", "baz": ""},
+			},
+		},
+		{
+			name: "remain option with interface values",
+			query: `
+				SELECT 'foo val' AS "nested.foo", 'bar val' AS "nested.bar"
+			`,
+			expected: struct {
+				Nested *struct {
+					Extra map[string]interface{} `db:",remain"`
+				}
+			}{
+				Nested: &struct {
+					Extra map[string]interface{} `db:",remain"`
+				}{
+					Extra: map[string]interface{}{"foo": "foo val", "bar": "bar val"},
+				},
+			},
+		},
+		{
+			name: "nested remain option collects only columns of the nested struct",
+			query: `
+				SELECT 'foo val' AS foo, 'bar val' AS "nested.bar", 'baz val' AS "nested.baz"
+			`,
+			expected: struct {
+				Foo    string
+				Nested struct {
+					Bar   string
+					Extra map[string]string `db:",remain"`
+				}
+			}{
+				Foo: "foo val",
+				Nested: struct {
+					Bar   string
+					Extra map[string]string `db:",remain"`
+				}{
+					Bar:   "bar val",
+					Extra: map[string]string{"baz": "baz val"},
+				},
+			},
+		},
+		{
+			name: "remain option with column aliases",
+			query: `
+				SELECT 'foo val' AS foo_old, 'bar val' AS bar
+			`,
+			expected: struct {
+				Foo   string            `db:"foo|foo_old"`
+				Extra map[string]string `db:",remain"`
+			}{
+				Foo:   "foo val",
+				Extra: map[string]string{"bar": "bar val"},
+			},
+		},
+		{
+			name: "remain option with column families and NULL values",
+			query: `
+				SELECT 'red' AS attr_color, NULL::TEXT AS attr_size, 42 AS bar, NULL::INT AS baz
+			`,
+			expected: struct {
+				Attrs map[string]string `db:"attr_*"`
+				Extra map[string]int64  `db:",remain"`
+			}{
+				Attrs: map[string]string{"color": "red", "size": ""},
+				Extra: map[string]int64{"bar": 42, "baz": 0},
+			},
+		},
+		{
+			name: "column families",
+			query: `
+				SELECT 'foo val' AS foo, 'red' AS attr_color, '3' AS phone_10, 'L' AS attr_size, '1' AS phone_2
+			`,
+			expected: struct {
+				Foo    string
+				Attrs  map[string]string `db:"attr_*"`
+				Phones []string          `db:"phone_#"`
+			}{
+				Foo:    "foo val",
+				Attrs:  map[string]string{"color": "red", "size": "L"},
+				Phones: []string{"1", "3"},
+			},
+		},
+		{
+			name: "inline and prefix options",
+			query: `
+				SELECT 'foo val' AS city, 'bar val' AS bar_city
+			`,
+			expected: struct {
+				Foo Address  `db:"foo,inline"`
+				Bar *Address `db:",prefix=bar_"`
+			}{
+				Foo: Address{City: "foo val"},
+				Bar: &Address{City: "bar val"},
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			api, err := getAPI(tc.opts...)
+			require.NoError(t, err)
+			rows := queryRows(t, tc.query)
+			defer rows.Close() //nolint: errcheck
+			dst := allocateDestination(tc.expected)
+			rows.Next()
+			err = api.ScanRow(dst, rows)
+			require.NoError(t, err)
+			requireNoRowsErrorsAndClose(t, rows)
+			assertDestinationEqual(t, tc.expected, dst)
+		})
+	}
+}
+
+func TestRowScanner_Scan_invalidTagOptions_returnsErr(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name        string
+		dst         interface{}
+		expectedErr string
+	}{
+		{
+			name: "required column is missing",
+			dst: &struct {
+				Foo string
+				Bar string
+				Baz string `db:"baz,required"`
+				Qux string `db:"qux,required"`
+			}{},
+			expectedErr: "doing scan: starting: scany: rows don't contain columns of required fields: baz, qux",
+		},
+		{
+			name: "rows contain several aliases of a field",
+			dst: &struct {
+				Foo string `db:"foo|bar"`
+			}{},
+			expectedErr: "doing scan: starting: scany: rows contain more than one alias of the same field: 'foo' and 'bar'",
+		},
+		{
+			name: "remain option for non-map field",
+			dst: &struct {
+				Foo   string
+				Extra []string `db:",remain"`
+			}{},
+			expectedErr: "doing scan: starting: scany: map columns to fields of " +
+				"struct { Foo string; Extra []string \"db:\\\",remain\\\"\" }: " +
+				"scany: field Extra: \"remain\" tag option requires a map with string keys, got []string",
+		},
+		{
+			name: "nested remain option doesn't collect root columns",
+			dst: &struct {
+				Foo    string
+				Nested struct {
+					Extra map[string]string `db:",remain"`
+				}
+			}{},
+			expectedErr: "doing scan: scanFn: scany: column: 'bar': no corresponding field found, or it's unexported in " +
+				"struct { Foo string; Nested struct { Extra map[string]string \"db:\\\",remain\\\"\" } }",
+		},
+		{
+			name: "column family for non-slice field",
+			dst: &struct {
+				Foo string
+				Bar string `db:"bar_#"`
+			}{},
+			expectedErr: "doing scan: starting: scany: map columns to fields of " +
+				"struct { Foo string; Bar string \"db:\\\"bar_#\\\"\" }: " +
+				"scany: field Bar: column pattern \"bar_#\" requires a slice, got string",
+		},
+		{
+			name: "inline option for non-struct field",
+			dst: &struct {
+				Foo string `db:"foo,inline"`
+				Bar string
+			}{},
+			expectedErr: "doing scan: starting: scany: map columns to fields of " +
+				"struct { Foo string \"db:\\\"foo,inline\\\"\"; Bar string }: " +
+				"scany: field Foo: \"inline\" and \"prefix\" tag options are allowed only for struct fields",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rows := queryRows(t, singleRowsQuery)
+			err := scan(t, tc.dst, rows)
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestNewAPI_WithStructTagKeys_noKeys_returnsErr(t *testing.T) {
+	t.Parallel()
+	api, err := dbscan.NewAPI(dbscan.WithStructTagKeys())
+	assert.EqualError(t, err, "scany: at least one struct tag key is required")
+	assert.Nil(t, api)
+}
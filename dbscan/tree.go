@@ -0,0 +1,317 @@
+package dbscan
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ScanTree is a package-level helper function that uses the DefaultAPI object.
+// See API.ScanTree for details.
+func ScanTree(dst interface{}, rows Rows) error {
+	return DefaultAPI.ScanTree(dst, rows)
+}
+
+// ScanTree scans adjacency-list rows, like results of a recursive CTE, into a tree and stores its roots into dst.
+// The destination must be a pointer to a slice of pointers to structs, like *[]*Category,
+// the struct must have fields with the tree tag option:
+//
+//	type Category struct {
+//		ID       int64       `db:"id,tree=id"`
+//		ParentID *int64      `db:"parent_id,tree=parent"`
+//		Name     string
+//		Children []*Category `db:",tree=children"`
+//	}
+//
+// Rows are scanned the same way as ScanAll does, then every node is appended to the children of its parent.
+// Nodes with NULL parent are roots, as well as nodes with zero parent, unless there is a node with zero id:
+// in that case zero parent references that node, like any other parent value.
+// Roots and children keep the row order.
+// The parent field must be of the same kind of type as the id field, like *int32 parent and int64 id,
+// ints, unsigned ints and floats aren't mixed.
+//
+// If the parent of a node isn't found, ScanTree returns *TreeOrphansError.
+// If nodes form a cycle, so they aren't reachable from any root, ScanTree returns *TreeCycleError.
+// In both cases the destination isn't modified.
+func (api *API) ScanTree(dst interface{}, rows Rows) error {
+	defer rows.Close() //nolint: errcheck
+	dstValue, err := parseDestination(dst)
+	if err != nil {
+		return fmt.Errorf("scany: parsing destination: %w", err)
+	}
+	tm, err := api.getTreeMeta(dstValue.Type())
+	if err != nil {
+		return err
+	}
+	nodes := reflect.New(dstValue.Type())
+	if err := api.ScanAll(nodes.Interface(), rows); err != nil {
+		return err
+	}
+	roots, err := tm.link(nodes.Elem())
+	if err != nil {
+		return err
+	}
+	dstValue.Set(roots)
+	return nil
+}
+
+// TreeOrphan is a node whose parent isn't found, see TreeOrphansError.
+type TreeOrphan struct {
+	ID       interface{}
+	ParentID interface{}
+}
+
+// TreeOrphansError is returned by ScanTree if parents of some nodes aren't found.
+type TreeOrphansError struct {
+	Orphans []TreeOrphan
+}
+
+func (e *TreeOrphansError) Error() string {
+	reports := make([]string, len(e.Orphans))
+	for i, o := range e.Orphans {
+		reports[i] = fmt.Sprintf("node %v with parent %v", o.ID, o.ParentID)
+	}
+	return "scany: parents of tree nodes aren't found: " + strings.Join(reports, ", ")
+}
+
+// TreeCycleError is returned by ScanTree if nodes form cycles and aren't reachable from any root.
+type TreeCycleError struct {
+	// IDs contains ids of all nodes in cycles and their descendants in the row order.
+	IDs []interface{}
+}
+
+func (e *TreeCycleError) Error() string {
+	ids := make([]string, len(e.IDs))
+	for i, id := range e.IDs {
+		ids[i] = fmt.Sprint(id)
+	}
+	return "scany: tree nodes form a cycle: " + strings.Join(ids, ", ")
+}
+
+// treeMeta contains indexes of the tree fields of the node struct.
+type treeMeta struct {
+	idIndex       []int
+	parentIndex   []int
+	childrenIndex []int
+	// keyType is the type of id values, parent values are converted to it.
+	keyType reflect.Type
+}
+
+func (api *API) getTreeMeta(sliceType reflect.Type) (*treeMeta, error) {
+	if sliceType.Kind() != reflect.Slice || sliceType.Elem().Kind() != reflect.Ptr ||
+		!api.isStructValue(sliceType.Elem().Elem()) {
+		return nil, fmt.Errorf("scany: tree destination must be a slice of pointers to structs, got: %v", sliceType)
+	}
+	nodeType := sliceType.Elem().Elem()
+	mapping, err := api.getStructMapping(nodeType)
+	if err != nil {
+		return nil, fmt.Errorf("scany: map columns to fields of %v: %w", nodeType, err)
+	}
+	tm := &treeMeta{}
+	if err := tm.setKeyIndexes(nodeType, mapping); err != nil {
+		return nil, err
+	}
+	if mapping.treeChildren != nil {
+		tm.childrenIndex = mapping.treeChildren.Index
+	}
+	if tm.idIndex == nil || tm.parentIndex == nil || tm.childrenIndex == nil {
+		return nil, fmt.Errorf("scany: %v must have fields with %q, %q and %q tag options", nodeType,
+			TagOptionTree+"="+TreeRoleID, TagOptionTree+"="+TreeRoleParent, TagOptionTree+"="+TreeRoleChildren)
+	}
+	if childrenType := nodeType.FieldByIndex(tm.childrenIndex).Type; childrenType != sliceType {
+		return nil, fmt.Errorf("scany: tree children field %s must be of type %v, got: %v",
+			fieldPath(nodeType, tm.childrenIndex), sliceType, childrenType)
+	}
+	if err := tm.setKeyType(nodeType); err != nil {
+		return nil, err
+	}
+	return tm, nil
+}
+
+// setKeyType sets the type of id values and makes sure parent values can be compared with them.
+func (tm *treeMeta) setKeyType(nodeType reflect.Type) error {
+	idType := nodeType.FieldByIndex(tm.idIndex).Type
+	tm.keyType = treeKeyType(idType)
+	if !tm.keyType.Comparable() {
+		return fmt.Errorf("scany: tree id field %s must be of a comparable type, got: %v",
+			fieldPath(nodeType, tm.idIndex), idType)
+	}
+	parentType := nodeType.FieldByIndex(tm.parentIndex).Type
+	if !isTreeKeyCompatible(treeKeyType(parentType), tm.keyType) {
+		return fmt.Errorf("scany: tree parent field %s of type %v doesn't match id field %s of type %v",
+			fieldPath(nodeType, tm.parentIndex), parentType, fieldPath(nodeType, tm.idIndex), idType)
+	}
+	return nil
+}
+
+// isTreeKeyCompatible reports whether parent values can be converted to the id type to be compared with ids.
+// Conversions between different kinds of types, like int to string, don't preserve the value, so they aren't allowed.
+func isTreeKeyCompatible(parentType, idType reflect.Type) bool {
+	return parentType == idType ||
+		treeKeyKind(parentType.Kind()) == treeKeyKind(idType.Kind()) && parentType.ConvertibleTo(idType)
+}
+
+// treeKeyKind returns the same kind for numeric types that can be converted to each other without surprises.
+func treeKeyKind(kind reflect.Kind) reflect.Kind {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.Int
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return reflect.Uint
+	case reflect.Float32, reflect.Float64:
+		return reflect.Float64
+	default:
+		return kind
+	}
+}
+
+// setKeyIndexes finds the id and parent fields of the node struct.
+func (tm *treeMeta) setKeyIndexes(nodeType reflect.Type, mapping *structMapping) error {
+	for _, field := range mapping.columns {
+		var index *[]int
+		switch field.TreeRole {
+		case TreeRoleID:
+			index = &tm.idIndex
+		case TreeRoleParent:
+			index = &tm.parentIndex
+		default:
+			continue
+		}
+		if *index != nil && !equalIndexes(*index, field.Index) {
+			return fmt.Errorf("scany: %v has more than one field with %q tag option",
+				nodeType, TagOptionTree+"="+field.TreeRole)
+		}
+		*index = field.Index
+	}
+	return nil
+}
+
+// setTreeChildren sets the field that receives child nodes in ScanTree.
+func (m *structMapping) setTreeChildren(field reflect.StructField, index []int) error {
+	if m.treeChildren != nil {
+		return fmt.Errorf("scany: field %s: only one field can have %q tag option",
+			field.Name, TagOptionTree+"="+TreeRoleChildren)
+	}
+	m.treeChildren = &fieldMeta{Index: index}
+	return nil
+}
+
+func equalIndexes(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// link appends every node to the children of its parent and returns the roots.
+func (tm *treeMeta) link(nodes reflect.Value) (reflect.Value, error) {
+	byID := make(map[interface{}]reflect.Value, nodes.Len())
+	for i := 0; i < nodes.Len(); i++ {
+		node := nodes.Index(i).Elem()
+		if id, ok := tm.treeKey(node, tm.idIndex); ok {
+			byID[id] = node
+		}
+	}
+	roots := reflect.MakeSlice(nodes.Type(), 0, 0)
+	var orphans []TreeOrphan
+	for i := 0; i < nodes.Len(); i++ {
+		nodePtr := nodes.Index(i)
+		parentID, ok := tm.treeKey(nodePtr.Elem(), tm.parentIndex)
+		parent, found := byID[parentID]
+		switch {
+		case found && ok:
+			children := parent.FieldByIndex(tm.childrenIndex)
+			children.Set(reflect.Append(children, nodePtr))
+		case !ok || reflect.ValueOf(parentID).IsZero():
+			// Zero parent is checked after the lookup, so it references the node with zero id if there is one.
+			roots = reflect.Append(roots, nodePtr)
+		default:
+			id, _ := tm.treeKey(nodePtr.Elem(), tm.idIndex)
+			orphans = append(orphans, TreeOrphan{ID: id, ParentID: parentID})
+		}
+	}
+	if len(orphans) > 0 {
+		return reflect.Value{}, &TreeOrphansError{Orphans: orphans}
+	}
+	if err := tm.checkReachable(nodes, roots); err != nil {
+		return reflect.Value{}, err
+	}
+	return roots, nil
+}
+
+// checkReachable makes sure that all nodes are reachable from the roots, otherwise some of them form a cycle.
+func (tm *treeMeta) checkReachable(nodes, roots reflect.Value) error {
+	reached := make(map[uintptr]struct{}, nodes.Len())
+	queue := make([]reflect.Value, 0, nodes.Len())
+	for i := 0; i < roots.Len(); i++ {
+		queue = append(queue, roots.Index(i))
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		reached[node.Pointer()] = struct{}{}
+		children := node.Elem().FieldByIndex(tm.childrenIndex)
+		for i := 0; i < children.Len(); i++ {
+			queue = append(queue, children.Index(i))
+		}
+	}
+	if len(reached) == nodes.Len() {
+		return nil
+	}
+	cycleErr := &TreeCycleError{}
+	for i := 0; i < nodes.Len(); i++ {
+		if _, ok := reached[nodes.Index(i).Pointer()]; !ok {
+			id, _ := tm.treeKey(nodes.Index(i).Elem(), tm.idIndex)
+			cycleErr.IDs = append(cycleErr.IDs, id)
+		}
+	}
+	return cycleErr
+}
+
+// treeKeyType returns the type of id values used as map keys.
+func treeKeyType(t reflect.Type) reflect.Type {
+	for {
+		switch {
+		case t.Kind() == reflect.Ptr:
+			t = t.Elem()
+		case isNullType(t):
+			t = t.Field(nullValueFieldIndex).Type
+		case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+			return reflect.TypeOf("")
+		default:
+			return t
+		}
+	}
+}
+
+// treeKey returns the id value of the field that can be used as a map key, it's false if the value is NULL.
+// Pointers and Null values are unwrapped and the value is converted to the id type,
+// so *int32 parent matches int64 id, getTreeMeta makes sure the conversion is valid.
+func (tm *treeMeta) treeKey(node reflect.Value, index []int) (interface{}, bool) {
+	v, ok := nestedValue(node, index)
+	for ok {
+		switch {
+		case v.Kind() == reflect.Ptr:
+			ok = !v.IsNil()
+			if ok {
+				v = v.Elem()
+			}
+		case isNullType(v.Type()):
+			ok = v.Field(nullValidFieldIndex).Bool()
+			v = v.Field(nullValueFieldIndex)
+		case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+			// Byte slices aren't comparable, so they are compared by their string form.
+			return string(v.Bytes()), true
+		case v.Type() != tm.keyType && v.Type().ConvertibleTo(tm.keyType):
+			return v.Convert(tm.keyType).Interface(), true
+		default:
+			return v.Interface(), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,142 @@
+package dbscan_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/georgysavva/scany/v2/dbscan"
+)
+
+type treeNode struct {
+	ID       int64       `db:"id,tree=id"`
+	ParentID *int64      `db:"parent_id,tree=parent"`
+	Foo      string      `db:"foo"`
+	Children []*treeNode `db:",tree=children"`
+}
+
+func TestScanTree(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT *
+		FROM (
+			VALUES (1::INT8, NULL::INT8, 'foo val'), (2, 1, 'foo val 2'), (3, 2, 'foo val 3'),
+				(4, 1, 'foo val 4'), (5, NULL, 'foo val 5')
+		) AS t (id, parent_id, foo)
+	`
+	one, two := int64(1), int64(2)
+	node3 := &treeNode{ID: 3, ParentID: &two, Foo: "foo val 3"}
+	node2 := &treeNode{ID: 2, ParentID: &one, Foo: "foo val 2", Children: []*treeNode{node3}}
+	node4 := &treeNode{ID: 4, ParentID: &one, Foo: "foo val 4"}
+	expected := []*treeNode{
+		{ID: 1, Foo: "foo val", Children: []*treeNode{node2, node4}},
+		{ID: 5, Foo: "foo val 5"},
+	}
+
+	var got []*treeNode
+	err := testAPI.ScanTree(&got, queryRows(t, query))
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
+
+func TestScanTree_zeroParentWithZeroIDNode(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT *
+		FROM (
+			VALUES (0::INT8, NULL::INT8, 'foo val'), (1, 0, 'foo val 2')
+		) AS t (id, parent_id, foo)
+	`
+	zero := int64(0)
+	expected := []*treeNode{
+		{ID: 0, Foo: "foo val", Children: []*treeNode{{ID: 1, ParentID: &zero, Foo: "foo val 2"}}},
+	}
+
+	var got []*treeNode
+	err := testAPI.ScanTree(&got, queryRows(t, query))
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
+
+func TestScanTree_orphans_returnsErr(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT *
+		FROM (
+			VALUES (1::INT8, NULL::INT8, 'foo val'), (2, 7, 'foo val 2'), (3, 1, 'foo val 3')
+		) AS t (id, parent_id, foo)
+	`
+	var got []*treeNode
+	err := testAPI.ScanTree(&got, queryRows(t, query))
+
+	var orphansErr *dbscan.TreeOrphansError
+	require.True(t, errors.As(err, &orphansErr))
+	assert.Equal(t, []dbscan.TreeOrphan{{ID: int64(2), ParentID: int64(7)}}, orphansErr.Orphans)
+	assert.EqualError(t, err, "scany: parents of tree nodes aren't found: node 2 with parent 7")
+	assert.Nil(t, got)
+}
+
+func TestScanTree_cycle_returnsErr(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT *
+		FROM (
+			VALUES (1::INT8, NULL::INT8, 'foo val'), (2, 3, 'foo val 2'), (3, 2, 'foo val 3'), (4, 4, 'foo val 4')
+		) AS t (id, parent_id, foo)
+	`
+	var got []*treeNode
+	err := testAPI.ScanTree(&got, queryRows(t, query))
+
+	var cycleErr *dbscan.TreeCycleError
+	require.True(t, errors.As(err, &cycleErr))
+	assert.Equal(t, []interface{}{int64(2), int64(3), int64(4)}, cycleErr.IDs)
+	assert.EqualError(t, err, "scany: tree nodes form a cycle: 2, 3, 4")
+}
+
+func TestScanTree_invalidDestination_returnsErr(t *testing.T) {
+	t.Parallel()
+	type noChildren struct {
+		ID       int64  `db:"id,tree=id"`
+		ParentID *int64 `db:"parent_id,tree=parent"`
+	}
+	type stringParent struct {
+		ID       int64           `db:"id,tree=id"`
+		ParentID *string         `db:"parent_id,tree=parent"`
+		Children []*stringParent `db:",tree=children"`
+	}
+	cases := []struct {
+		name        string
+		dst         interface{}
+		expectedErr string
+	}{
+		{
+			name:        "slice of structs by value",
+			dst:         &[]treeNode{},
+			expectedErr: "scany: tree destination must be a slice of pointers to structs, got: []dbscan_test.treeNode",
+		},
+		{
+			name: "no children field",
+			dst:  &[]*noChildren{},
+			expectedErr: "scany: dbscan_test.noChildren must have fields with " +
+				`"tree=id", "tree=parent" and "tree=children" tag options`,
+		},
+		{
+			name: "parent of another kind than id",
+			dst:  &[]*stringParent{},
+			expectedErr: "scany: tree parent field ParentID of type *string doesn't match " +
+				"id field ID of type int64",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := testAPI.ScanTree(tc.dst, queryRows(t, singleRowsQuery))
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package dbscan
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// RowUnmarshaler can be implemented by a destination type to decode the whole row by itself
+// instead of dbscan mapping columns to struct fields via reflection.
+// It's useful for types that can't be described with struct tags,
+// like value objects with unexported fields or generated types.
+//
+// UnmarshalRow receives the row columns and the scan function
+// that works the same way as Rows.Scan: it expects exactly one destination per column.
+//
+// If a RowUnmarshaler is nested into a struct, it receives all columns prefixed with its field column name,
+// without the prefix. For example, a field `db:"address"` receives "address.city" column as "city".
+// Note that the scan function for a nested RowUnmarshaler calls Rows.Scan once more for the current row,
+// so the database library must support scanning the same row multiple times,
+// which is true for both database/sql and pgx.
+// An embedded RowUnmarshaler field is handled the same way as a nested one,
+// UnmarshalRow promoted from it doesn't make the outer struct a RowUnmarshaler.
+// Since an embedded field has no column prefix by default, it must have one in the struct tag,
+// for example `db:"money"`, and its type must be exported, otherwise mapping fails.
+type RowUnmarshaler interface {
+	UnmarshalRow(columns []string, scan func(dest ...interface{}) error) error
+}
+
+var rowUnmarshalerType = reflect.TypeOf((*RowUnmarshaler)(nil)).Elem()
+
+// implementsRowUnmarshaler reports whether the type or a pointer to it implements RowUnmarshaler.
+// UnmarshalRow promoted from an embedded field doesn't count, it can decode only the embedded part,
+// so such struct is mapped field by field and the embedded field is handled as a nested RowUnmarshaler.
+func implementsRowUnmarshaler(t reflect.Type) bool {
+	if t.Kind() != reflect.Ptr {
+		t = reflect.PtrTo(t)
+	}
+	return t.Implements(rowUnmarshalerType) && !isPromotedMethod(t.Elem(), rowUnmarshalerMethod)
+}
+
+const rowUnmarshalerMethod = "UnmarshalRow"
+
+// isPromotedMethod reports whether the method of the struct type or a pointer to it
+// is promoted from an embedded field rather than declared for the type itself.
+func isPromotedMethod(t reflect.Type, name string) bool {
+	if t.Kind() != reflect.Struct || !embedsMethod(t, name) {
+		return false
+	}
+	method, ok := t.MethodByName(name)
+	if !ok {
+		method, ok = reflect.PtrTo(t).MethodByName(name)
+		if !ok {
+			return false
+		}
+	}
+	// The method of the type and the one of the embedded field can't be compared directly,
+	// and reflect doesn't tell whether a method is promoted.
+	// The gc compiler implements promoted methods via wrappers reported as "<autogenerated>",
+	// while a method declared for the type is a regular function with its source file.
+	// This relies on the gc toolchain: if the wrapper isn't recognized, for example with gccgo,
+	// the method is treated as declared and the struct is unmarshaled as a whole by it.
+	fn := runtime.FuncForPC(method.Func.Pointer())
+	if fn == nil {
+		return false
+	}
+	file, _ := fn.FileLine(fn.Entry())
+	return file == "<autogenerated>"
+}
+
+// embedsMethod reports whether an embedded field of the struct type or a pointer to it has the method.
+func embedsMethod(t reflect.Type, name string) bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.Anonymous {
+			continue
+		}
+		ft := field.Type
+		if ft.Kind() != reflect.Ptr {
+			ft = reflect.PtrTo(ft)
+		}
+		if _, ok := ft.MethodByName(name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// unmarshalerField is a nested RowUnmarshaler field and the columns it receives.
+type unmarshalerField struct {
+	meta *fieldMeta
+	// positions contains indexes of the columns in the row.
+	positions []int
+	// columns contains the column names without the field prefix.
+	columns []string
+}
+
+func (rs *RowScanner) scanRowUnmarshaler(dstValue reflect.Value) error {
+	unmarshaler := dstValue.Addr().Interface().(RowUnmarshaler)
+	if err := unmarshaler.UnmarshalRow(rs.columns, rs.rows.Scan); err != nil {
+		return fmt.Errorf("scany: unmarshal row into %v: %w", dstValue.Type(), err)
+	}
+	return nil
+}
+
+// getUnmarshalerFields finds the columns that belong to nested RowUnmarshaler fields.
+func (rs *RowScanner) getUnmarshalerFields() []*unmarshalerField {
+	var fields []*unmarshalerField
+	for prefix, field := range rs.columnToFieldIndex {
+		if !field.RowUnmarshaler {
+			continue
+		}
+		uf := &unmarshalerField{meta: field}
+		for i, column := range rs.columns {
+			name, ok := trimColumnPrefix(column, prefix, rs.api.columnSeparator)
+			if !ok {
+				continue
+			}
+			uf.positions = append(uf.positions, i)
+			uf.columns = append(uf.columns, name)
+			if rs.unmarshaledColumns == nil {
+				rs.unmarshaledColumns = make(map[string]struct{})
+			}
+			rs.unmarshaledColumns[column] = struct{}{}
+		}
+		if len(uf.columns) > 0 {
+			fields = append(fields, uf)
+		}
+	}
+	// Map iteration order is random, keep the order of columns in the row.
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].positions[0] < fields[j].positions[0]
+	})
+	return fields
+}
+
+// unmarshalerScanTargets sets scan targets that discard values of nested RowUnmarshaler columns,
+// those columns are scanned by the nested fields later.
+func (rs *RowScanner) unmarshalerScanTargets(scans []interface{}) {
+	for _, uf := range rs.unmarshalerFields {
+		for _, position := range uf.positions {
+			scans[position] = &Discard{}
+		}
+	}
+}
+
+// trimColumnPrefix returns the column without the prefix and the separator,
+// it reports false if the column doesn't have such prefix.
+// The column that equals to the prefix itself is returned as is.
+func trimColumnPrefix(column, prefix, separator string) (string, bool) {
+	if prefix == "" {
+		return "", false
+	}
+	if column == prefix {
+		return column, true
+	}
+	if !strings.HasPrefix(column, prefix+separator) {
+		return "", false
+	}
+	return column[len(prefix)+len(separator):], true
+}
+
+func (rs *RowScanner) scanUnmarshalerField(structValue reflect.Value, uf *unmarshalerField) error {
+	initializeNested(structValue, uf.meta.Index)
+	fieldVal := structValue.FieldByIndex(uf.meta.Index)
+	var target interface{}
+	if fieldVal.Kind() == reflect.Ptr {
+		if fieldVal.IsNil() {
+			fieldVal.Set(reflect.New(fieldVal.Type().Elem()))
+		}
+		target = fieldVal.Interface()
+	} else {
+		target = fieldVal.Addr().Interface()
+	}
+	scan := func(dest ...interface{}) error {
+		if len(dest) != len(uf.positions) {
+			return fmt.Errorf("scany: expected %d destination arguments in scan, got: %d", len(uf.positions), len(dest))
+		}
+		scans := discardScanTargets(len(rs.columns))
+		for i, position := range uf.positions {
+			scans[position] = dest[i]
+		}
+		return rs.rows.Scan(scans...)
+	}
+	if err := target.(RowUnmarshaler).UnmarshalRow(uf.columns, scan); err != nil {
+		return fmt.Errorf("scany: unmarshal row into field %v: %w", fieldVal.Type(), err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package dbscan_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testMoney struct {
+	amount   int64
+	currency string
+	columns  []string
+}
+
+func (m *testMoney) UnmarshalRow(columns []string, scan func(dest ...interface{}) error) error {
+	m.columns = columns
+	return scan(&m.amount, &m.currency)
+}
+
+// EmbeddedMoney is exported, so it can be embedded as a RowUnmarshaler.
+type EmbeddedMoney = testMoney
+
+// testPricedItem declares its own UnmarshalRow, which takes precedence over the embedded one.
+type testPricedItem struct {
+	testMoney
+	Name string
+}
+
+func (i *testPricedItem) UnmarshalRow(columns []string, scan func(dest ...interface{}) error) error {
+	return scan(&i.Name, &i.amount, &i.currency)
+}
+
+func TestRowScanner_Scan_rowUnmarshalerDestination(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		query    string
+		expected interface{}
+	}{
+		{
+			name: "top-level destination",
+			query: `
+				SELECT 10 AS amount, 'USD' AS currency
+			`,
+			expected: testMoney{amount: 10, currency: "USD", columns: []string{"amount", "currency"}},
+		},
+		{
+			name: "nested field receives columns without prefix",
+			query: `
+				SELECT 10 AS "price.amount", 'foo val' AS foo, 'USD' AS "price.currency"
+			`,
+			expected: struct {
+				Foo   string
+				Price testMoney
+			}{
+				Foo:   "foo val",
+				Price: testMoney{amount: 10, currency: "USD", columns: []string{"amount", "currency"}},
+			},
+		},
+		{
+			name: "nested field by pointer",
+			query: `
+				SELECT 'foo val' AS foo, 10 AS "price.amount", 'EUR' AS "price.currency"
+			`,
+			expected: struct {
+				Foo   string
+				Price *testMoney
+			}{
+				Foo:   "foo val",
+				Price: &testMoney{amount: 10, currency: "EUR", columns: []string{"amount", "currency"}},
+			},
+		},
+		{
+			name: "embedded field doesn't unmarshal the outer struct",
+			query: `
+				SELECT 10 AS "price.amount", 'foo val' AS foo, 'USD' AS "price.currency"
+			`,
+			expected: struct {
+				EmbeddedMoney `db:"price"`
+				Foo           string
+			}{
+				EmbeddedMoney: EmbeddedMoney{amount: 10, currency: "USD", columns: []string{"amount", "currency"}},
+				Foo:           "foo val",
+			},
+		},
+		{
+			name: "own method takes precedence over the embedded one",
+			query: `
+				SELECT 'foo val' AS name, 10 AS amount, 'USD' AS currency
+			`,
+			expected: testPricedItem{testMoney: testMoney{amount: 10, currency: "USD"}, Name: "foo val"},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rows := queryRows(t, tc.query)
+			defer rows.Close() //nolint: errcheck
+			dst := allocateDestination(tc.expected)
+			rows.Next()
+			err := testAPI.ScanRow(dst, rows)
+			require.NoError(t, err)
+			requireNoRowsErrorsAndClose(t, rows)
+			assertDestinationEqual(t, tc.expected, dst)
+		})
+	}
+}
+
+func TestScanAll_rowUnmarshalerSlice(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT * FROM (
+			VALUES (1, 'USD'), (2, 'EUR')
+		) AS t (amount, currency)
+	`
+	rows := queryRows(t, query)
+	var got []*testMoney
+	err := testAPI.ScanAll(&got, rows)
+	require.NoError(t, err)
+	expected := []*testMoney{
+		{amount: 1, currency: "USD", columns: []string{"amount", "currency"}},
+		{amount: 2, currency: "EUR", columns: []string{"amount", "currency"}},
+	}
+	assert.Equal(t, expected, got)
+}
+
+func TestRowScanner_Scan_rowUnmarshalerFails_returnsErr(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT 10 AS amount
+	`
+	rows := queryRows(t, query)
+	defer rows.Close() //nolint: errcheck
+	rows.Next()
+	dst := &testMoney{}
+	err := testAPI.ScanRow(dst, rows)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "scany: unmarshal row into dbscan_test.testMoney")
+}
+
+func TestRowScanner_Scan_embeddedRowUnmarshalerWithoutPrefix_returnsErr(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT 10 AS amount, 'USD' AS currency, 'foo val' AS foo
+	`
+	rows := queryRows(t, query)
+	defer rows.Close() //nolint: errcheck
+	rows.Next()
+	dst := &struct {
+		EmbeddedMoney
+		Foo string
+	}{}
+	err := testAPI.ScanRow(dst, rows)
+	require.Error(t, err)
+	expectedErr := "scany: field EmbeddedMoney: embedded RowUnmarshaler requires a column prefix in the struct tag"
+	assert.Contains(t, err.Error(), expectedErr)
+}
+
+func TestRowScanner_Scan_unexportedEmbeddedRowUnmarshaler_returnsErr(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT 10 AS "price.amount", 'USD' AS "price.currency"
+	`
+	rows := queryRows(t, query)
+	defer rows.Close() //nolint: errcheck
+	rows.Next()
+	dst := &struct {
+		testMoney `db:"price"`
+	}{}
+	err := testAPI.ScanRow(dst, rows)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "scany: field testMoney: embedded RowUnmarshaler must be exported")
+}
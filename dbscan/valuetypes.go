@@ -0,0 +1,58 @@
+package dbscan
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ValueTypesRows is an optional interface that Rows can implement to choose Go types for column values
+// scanned into interface destinations: interface map elements, Record values and []interface{}.
+// Without it such columns receive whatever values the database library returns,
+// for example database/sql drivers often return []byte for text and numeric columns.
+//
+// ValueTypes returns a type for every column, nil means the value is scanned as is.
+// The column is scanned into a pointer to the type, so NULL is still stored as nil.
+type ValueTypesRows interface {
+	Rows
+	ValueTypes() ([]reflect.Type, error)
+}
+
+// startValueTypes gets the Go types for column values if Rows provide them.
+func (rs *RowScanner) startValueTypes() error {
+	typedRows, ok := rs.rows.(ValueTypesRows)
+	if !ok {
+		return nil
+	}
+	valueTypes, err := typedRows.ValueTypes()
+	if err != nil {
+		return fmt.Errorf("scany: get rows value types: %w", err)
+	}
+	if len(valueTypes) != len(rs.columns) {
+		return fmt.Errorf("scany: got %d value types, but rows contain %d columns", len(valueTypes), len(rs.columns))
+	}
+	rs.valueTypes = valueTypes
+	return nil
+}
+
+// interfaceScanTarget is the same as scanTarget, but for interface values
+// it scans the column into the type provided by Rows, see ValueTypesRows.
+func (rs *RowScanner) interfaceScanTarget(value reflect.Value, position int) (interface{}, finishScanFunc) {
+	column := rs.columns[position]
+	if rs.valueTypes == nil || value.Kind() != reflect.Interface {
+		return rs.api.scanTarget(value, column, false /* nullable */)
+	}
+	valueType := rs.valueTypes[position]
+	if valueType == nil || !valueType.Implements(value.Type()) {
+		return rs.api.scanTarget(value, column, false /* nullable */)
+	}
+	holder := reflect.New(reflect.PtrTo(valueType))
+	finish := func() (bool, error) {
+		if holder.Elem().IsNil() {
+			value.Set(reflect.Zero(value.Type()))
+			return false, nil
+		}
+		value.Set(holder.Elem().Elem())
+		return true, nil
+	}
+	return holder.Interface(), finish
+}
@@ -0,0 +1,183 @@
+package dbscan
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Wildcards at the end of the column name in the struct tag, see "Column families" section in the package doc.
+const (
+	// mapWildcard matches any column suffix, the field must be a map with string keys.
+	mapWildcard = "*"
+	// sliceWildcard matches a numeric column suffix, the field must be a slice.
+	sliceWildcard = "#"
+)
+
+// wildcardField is a map or slice field that collects all columns matching the name pattern.
+type wildcardField struct {
+	meta *fieldMeta
+	// prefix is the column name before the wildcard.
+	prefix   string
+	wildcard string
+	// elemType is the type of the map or slice elements.
+	elemType reflect.Type
+}
+
+func isWildcardColumn(column string) bool {
+	return strings.HasSuffix(column, mapWildcard) || strings.HasSuffix(column, sliceWildcard)
+}
+
+// addWildcard adds the field that collects columns matching the pattern.
+func (m *structMapping) addWildcard(field reflect.StructField, index []int, pattern string) error {
+	wf := &wildcardField{
+		meta:     &fieldMeta{Index: index},
+		prefix:   pattern[:len(pattern)-1],
+		wildcard: pattern[len(pattern)-1:],
+	}
+	switch wf.wildcard {
+	case mapWildcard:
+		if field.Type.Kind() != reflect.Map || field.Type.Key().Kind() != reflect.String {
+			return fmt.Errorf("scany: field %s: column pattern %q requires a map with string keys, got %v",
+				field.Name, pattern, field.Type)
+		}
+	case sliceWildcard:
+		if field.Type.Kind() != reflect.Slice {
+			return fmt.Errorf("scany: field %s: column pattern %q requires a slice, got %v",
+				field.Name, pattern, field.Type)
+		}
+	}
+	wf.elemType = field.Type.Elem()
+	m.wildcards = append(m.wildcards, wf)
+	return nil
+}
+
+// wildcardColumns is a wildcard field and the columns of the current rows it collects.
+type wildcardColumns struct {
+	field *wildcardField
+	// positions contains indexes of the columns in the row.
+	// For slice fields they are ordered by the numeric suffix of the column.
+	positions []int
+	// keys contains the column suffixes, they are used as keys of map fields.
+	keys []string
+}
+
+// wildcardMatch is a column that matches a wildcard field.
+type wildcardMatch struct {
+	position int
+	key      string
+	number   int
+}
+
+// getWildcardColumns matches columns without a corresponding field against wildcard fields.
+// A column is collected by the first wildcard field it matches.
+func (rs *RowScanner) getWildcardColumns(fields []*wildcardField) []*wildcardColumns {
+	matches := make([][]*wildcardMatch, len(fields))
+	for i, column := range rs.columns {
+		if rs.columnFields[i] != nil {
+			continue
+		}
+		if _, ok := rs.unmarshaledColumns[column]; ok {
+			continue
+		}
+		for j, wf := range fields {
+			if match, ok := wf.match(column); ok {
+				match.position = i
+				matches[j] = append(matches[j], match)
+				break
+			}
+		}
+	}
+	var result []*wildcardColumns
+	for i, wf := range fields {
+		if len(matches[i]) == 0 {
+			continue
+		}
+		if wf.wildcard == sliceWildcard {
+			sort.SliceStable(matches[i], func(a, b int) bool { return matches[i][a].number < matches[i][b].number })
+		}
+		wc := &wildcardColumns{field: wf}
+		for _, match := range matches[i] {
+			wc.positions = append(wc.positions, match.position)
+			wc.keys = append(wc.keys, match.key)
+		}
+		result = append(result, wc)
+	}
+	return result
+}
+
+func (wf *wildcardField) match(column string) (*wildcardMatch, bool) {
+	if !strings.HasPrefix(column, wf.prefix) || len(column) == len(wf.prefix) {
+		return nil, false
+	}
+	match := &wildcardMatch{key: column[len(wf.prefix):]}
+	if wf.wildcard == sliceWildcard {
+		number, err := strconv.ParseUint(match.key, 10, 31)
+		if err != nil {
+			return nil, false
+		}
+		match.number = int(number)
+	}
+	return match, true
+}
+
+// wildcardScan contains values scanned from the current row for a wildcard field.
+type wildcardScan struct {
+	columns  *wildcardColumns
+	values   []reflect.Value
+	finishes []finishScanFunc
+}
+
+// wildcardScanTargets sets scan targets for columns of wildcard fields.
+func (rs *RowScanner) wildcardScanTargets(scans []interface{}) []*wildcardScan {
+	result := make([]*wildcardScan, 0, len(rs.wildcardColumns))
+	for _, wc := range rs.wildcardColumns {
+		ws := &wildcardScan{columns: wc, values: make([]reflect.Value, len(wc.positions))}
+		for i, position := range wc.positions {
+			ws.values[i] = reflect.New(wc.field.elemType).Elem()
+			var finish finishScanFunc
+			scans[position], finish = rs.api.scanTarget(ws.values[i], rs.columns[position], true /* nullable */)
+			if finish != nil {
+				ws.finishes = append(ws.finishes, finish)
+			}
+		}
+		result = append(result, ws)
+	}
+	return result
+}
+
+// fillWildcardFields stores the scanned values into wildcard fields.
+// Every row gets a new map or slice, so destinations don't share them.
+func (rs *RowScanner) fillWildcardFields(structValue reflect.Value, wildcards []*wildcardScan) error {
+	for _, ws := range wildcards {
+		for _, finish := range ws.finishes {
+			if _, err := finish(); err != nil {
+				return fmt.Errorf("scany: scan row into struct fields: %w", err)
+			}
+		}
+		wf := ws.columns.field
+		initializeNested(structValue, wf.meta.Index)
+		field := structValue.FieldByIndex(wf.meta.Index)
+		var collection reflect.Value
+		if wf.wildcard == mapWildcard {
+			collection = reflect.MakeMapWithSize(field.Type(), len(ws.values))
+		} else {
+			collection = reflect.MakeSlice(field.Type(), len(ws.values), len(ws.values))
+		}
+		for i, value := range ws.values {
+			if rs.api.normalizationEnabled() {
+				rs.api.normalizeValue(value, isPaddedColumn(rs.paddedColumns, ws.columns.positions[i]))
+			}
+			if wf.wildcard == mapWildcard {
+				key := reflect.ValueOf(ws.columns.keys[i]).Convert(field.Type().Key())
+				collection.SetMapIndex(key, value)
+			} else {
+				collection.Index(i).Set(value)
+			}
+		}
+		field.Set(collection)
+	}
+	return nil
+}
@@ -31,6 +31,15 @@ This happens because struct fields are always passed to the underlying pgx.Rows.
 and if the field type is *pgtype.Text, pgx.Rows.Scan() will receive **pgtype.Text type.
 pgx can't handle **pgtype.Text, since only *pgtype.Text implements pgx custom type interface.
 
+Dynamic values
+
+Columns scanned into interface values, like map[string]interface{} elements, dbscan.Record values
+or interface{} struct fields, get the values decoded according to the column type, like pgx.Rows.Values does,
+so they have the same Go types that pgx produces, for example pgtype.Numeric for numerics
+and [16]byte for UUIDs. Use WithUUIDAsString option to get UUIDs as strings instead:
+
+	api, err := pgxscan.NewAPI(dbscanAPI, pgxscan.WithUUIDAsString(true))
+
 Supported pgx version
 
 pgxscan v2 only works with pgx v5. So the import path of your pgx must be: "github.com/jackc/pgx/v5".
@@ -0,0 +1,61 @@
+package pgxscan
+
+import (
+	"reflect"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/georgysavva/scany/v2/dbscan"
+)
+
+// RegisterNullScanPlan makes pgx decode dbscan.Null[T] values natively:
+// the value is decoded into T by the codec of the column type, NULL makes the Null invalid.
+// Without it pgx falls back to the sql.Scanner implementation of Null,
+// which supports only the types that database/sql values can be converted into.
+//
+// Call it for the type map of every connection, for example in pgxpool.Config.AfterConnect:
+//
+//	config.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+//	    pgxscan.RegisterNullScanPlan(conn.TypeMap())
+//	    return nil
+//	}
+//
+// It matters only for Null values passed to pgx directly, like in pgx.Row.Scan,
+// pgxscan scans Null struct fields and destinations as *T anyway.
+func RegisterNullScanPlan(m *pgtype.Map) {
+	m.TryWrapScanPlanFuncs = append([]pgtype.TryWrapScanPlanFunc{tryWrapNullScanPlan}, m.TryWrapScanPlanFuncs...)
+}
+
+func tryWrapNullScanPlan(target interface{}) (pgtype.WrappedScanPlanNextSetter, interface{}, bool) {
+	dstValue := reflect.ValueOf(target)
+	if dstValue.Kind() != reflect.Ptr || dstValue.IsNil() || !dbscan.IsNullType(dstValue.Type().Elem()) {
+		return nil, nil, false
+	}
+	// The next plan is found for *T, it's the type of the value field of Null.
+	valueType := dstValue.Type().Elem().Field(0).Type
+	return &nullScanPlan{}, reflect.New(valueType).Interface(), true
+}
+
+// nullScanPlan scans into the value field of dbscan.Null and sets its validity.
+type nullScanPlan struct {
+	next pgtype.ScanPlan
+}
+
+// SetNext implements the pgtype.WrappedScanPlanNextSetter interface.
+func (plan *nullScanPlan) SetNext(next pgtype.ScanPlan) { plan.next = next }
+
+// Scan implements the pgtype.ScanPlan interface.
+func (plan *nullScanPlan) Scan(src []byte, target interface{}) error {
+	null := reflect.ValueOf(target).Elem()
+	if src == nil {
+		null.Set(reflect.Zero(null.Type()))
+		return nil
+	}
+	value := reflect.New(null.Field(0).Type())
+	if err := plan.next.Scan(src, value.Interface()); err != nil {
+		return err
+	}
+	null.Field(0).Set(value.Elem())
+	null.Field(1).SetBool(true)
+	return nil
+}
@@ -5,8 +5,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/georgysavva/scany/v2/dbscan"
@@ -30,22 +35,51 @@ func Select(ctx context.Context, db Querier, dst interface{}, query string, args
 	return DefaultAPI.Select(ctx, db, dst, query, args...)
 }
 
+// SelectWith is a package-level helper function that uses the DefaultAPI object.
+// See API.SelectWith for details.
+func SelectWith(ctx context.Context, db Querier, dst interface{}, query string,
+	opts []dbscan.ScanOption, args ...interface{},
+) error {
+	return DefaultAPI.SelectWith(ctx, db, dst, query, opts, args...)
+}
+
 // Get is a package-level helper function that uses the DefaultAPI object.
 // See API.Get for details.
 func Get(ctx context.Context, db Querier, dst interface{}, query string, args ...interface{}) error {
 	return DefaultAPI.Get(ctx, db, dst, query, args...)
 }
 
+// GetWith is a package-level helper function that uses the DefaultAPI object.
+// See API.GetWith for details.
+func GetWith(ctx context.Context, db Querier, dst interface{}, query string,
+	opts []dbscan.ScanOption, args ...interface{},
+) error {
+	return DefaultAPI.GetWith(ctx, db, dst, query, opts, args...)
+}
+
+// GetValues is a package-level helper function that uses the DefaultAPI object.
+// See API.GetValues for details.
+func GetValues(ctx context.Context, db Querier, query string, args ...interface{},
+) func(dst ...interface{}) error {
+	return DefaultAPI.GetValues(ctx, db, query, args...)
+}
+
 // ScanAll is a package-level helper function that uses the DefaultAPI object.
 // See API.ScanAll for details.
-func ScanAll(dst interface{}, rows pgx.Rows) error {
-	return DefaultAPI.ScanAll(dst, rows)
+func ScanAll(dst interface{}, rows pgx.Rows, opts ...dbscan.ScanOption) error {
+	return DefaultAPI.ScanAll(dst, rows, opts...)
+}
+
+// ScanOneValues is a package-level helper function that uses the DefaultAPI object.
+// See API.ScanOneValues for details.
+func ScanOneValues(rows pgx.Rows, dst ...interface{}) error {
+	return DefaultAPI.ScanOneValues(rows, dst...)
 }
 
 // ScanOne is a package-level helper function that uses the DefaultAPI object.
 // See API.ScanOne for details.
-func ScanOne(dst interface{}, rows pgx.Rows) error {
-	return DefaultAPI.ScanOne(dst, rows)
+func ScanOne(dst interface{}, rows pgx.Rows, opts ...dbscan.ScanOption) error {
+	return DefaultAPI.ScanOne(dst, rows, opts...)
 }
 
 // RowScanner is a wrapper around the dbscan.RowScanner type.
@@ -81,23 +115,52 @@ func NewDBScanAPI(opts ...dbscan.APIOption) (*dbscan.API, error) {
 // API is a wrapper around the dbscan.API type.
 // See dbscan.API for details.
 type API struct {
-	dbscanAPI *dbscan.API
+	dbscanAPI    *dbscan.API
+	uuidAsString bool
+}
+
+// APIOption is a function type that changes API configuration.
+type APIOption func(api *API)
+
+// WithUUIDAsString makes pgxscan decode UUID columns scanned into interface values
+// as strings in the canonical form, like "f47ac10b-58cc-4372-a567-0e02b2c3d479", instead of [16]byte.
+// It applies to UUID arrays as well.
+func WithUUIDAsString(enabled bool) APIOption {
+	return func(api *API) {
+		api.uuidAsString = enabled
+	}
 }
 
 // NewAPI creates new API instance from dbscan.API instance.
-func NewAPI(dbscanAPI *dbscan.API) (*API, error) {
+func NewAPI(dbscanAPI *dbscan.API, opts ...APIOption) (*API, error) {
 	api := &API{dbscanAPI: dbscanAPI}
+	for _, o := range opts {
+		o(api)
+	}
 	return api, nil
 }
 
+func (api *API) newRowsAdapter(rows pgx.Rows) *RowsAdapter {
+	return &RowsAdapter{Rows: rows, uuidAsString: api.uuidAsString}
+}
+
 // Select is a high-level function that queries rows from Querier and calls the ScanAll function.
 // See ScanAll for details.
 func (api *API) Select(ctx context.Context, db Querier, dst interface{}, query string, args ...interface{}) error {
+	return api.SelectWith(ctx, db, dst, query, nil, args...)
+}
+
+// SelectWith is the same as Select, but it passes the scan options to the ScanAll function:
+//
+//	err := SelectWith(ctx, db, &ordersByUser, query, []dbscan.ScanOption{dbscan.WithKeyColumn("user_id")}, since)
+func (api *API) SelectWith(ctx context.Context, db Querier, dst interface{}, query string,
+	opts []dbscan.ScanOption, args ...interface{},
+) error {
 	rows, err := db.Query(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("scany: query multiple result rows: %w", err)
 	}
-	if err := api.ScanAll(dst, rows); err != nil {
+	if err := api.ScanAll(dst, rows, opts...); err != nil {
 		return fmt.Errorf("scanning all: %w", err)
 	}
 	return nil
@@ -106,27 +169,71 @@ func (api *API) Select(ctx context.Context, db Querier, dst interface{}, query s
 // Get is a high-level function that queries rows from Querier and calls the ScanOne function.
 // See ScanOne for details.
 func (api *API) Get(ctx context.Context, db Querier, dst interface{}, query string, args ...interface{}) error {
+	return api.GetWith(ctx, db, dst, query, nil, args...)
+}
+
+// GetWith is the same as Get, but it passes the scan options to the ScanOne function:
+//
+//	err := GetWith(ctx, db, &user, query, []dbscan.ScanOption{dbscan.WithSparseFields(true)}, id)
+func (api *API) GetWith(ctx context.Context, db Querier, dst interface{}, query string,
+	opts []dbscan.ScanOption, args ...interface{},
+) error {
 	rows, err := db.Query(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("scany: query one result row: %w", err)
 	}
-	if err := api.ScanOne(dst, rows); err != nil {
+	if err := api.ScanOne(dst, rows, opts...); err != nil {
 		return fmt.Errorf("scanning one: %w", err)
 	}
 	return nil
 }
 
+// GetValues is the same as Get, but it scans the single row into multiple destinations by position:
+//
+//	var count int
+//	var maxAge *int
+//	err := GetValues(ctx, db, "SELECT count(*), max(age) FROM users")(&count, &maxAge)
+//
+// The query is executed when the returned function is called. See ScanOneValues for details.
+func (api *API) GetValues(ctx context.Context, db Querier, query string, args ...interface{},
+) func(dst ...interface{}) error {
+	return func(dst ...interface{}) error {
+		rows, err := db.Query(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("scany: query one result row: %w", err)
+		}
+		if err := api.ScanOneValues(rows, dst...); err != nil {
+			return fmt.Errorf("scanning one: %w", err)
+		}
+		return nil
+	}
+}
+
 // ScanAll is a wrapper around the dbscan.ScanAll function.
 // See dbscan.ScanAll for details.
-func (api *API) ScanAll(dst interface{}, rows pgx.Rows) error {
-	return api.dbscanAPI.ScanAll(dst, NewRowsAdapter(rows))
+func (api *API) ScanAll(dst interface{}, rows pgx.Rows, opts ...dbscan.ScanOption) error {
+	return api.dbscanAPI.ScanAll(dst, api.newRowsAdapter(rows), opts...)
 }
 
 // ScanOne is a wrapper around the dbscan.ScanOne function.
 // See dbscan.ScanOne for details. If no rows are found it
 // returns a pgx.ErrNoRows error.
-func (api *API) ScanOne(dst interface{}, rows pgx.Rows) error {
-	switch err := api.dbscanAPI.ScanOne(dst, NewRowsAdapter(rows)); {
+func (api *API) ScanOne(dst interface{}, rows pgx.Rows, opts ...dbscan.ScanOption) error {
+	switch err := api.dbscanAPI.ScanOne(dst, api.newRowsAdapter(rows), opts...); {
+	case dbscan.NotFound(err):
+		return fmt.Errorf("%w", pgx.ErrNoRows)
+	case err != nil:
+		return fmt.Errorf("%w", err)
+	default:
+		return nil
+	}
+}
+
+// ScanOneValues is a wrapper around the dbscan.ScanOneValues function.
+// See dbscan.ScanOneValues for details. If no rows are found it
+// returns a pgx.ErrNoRows error.
+func (api *API) ScanOneValues(rows pgx.Rows, dst ...interface{}) error {
+	switch err := api.dbscanAPI.ScanOneValues(api.newRowsAdapter(rows), dst...); {
 	case dbscan.NotFound(err):
 		return fmt.Errorf("%w", pgx.ErrNoRows)
 	case err != nil:
@@ -144,20 +251,21 @@ func NotFound(err error) bool {
 
 // NewRowScanner returns a new RowScanner instance.
 func (api *API) NewRowScanner(rows pgx.Rows) *RowScanner {
-	ra := NewRowsAdapter(rows)
+	ra := api.newRowsAdapter(rows)
 	return &RowScanner{RowScanner: api.dbscanAPI.NewRowScanner(ra)}
 }
 
 // ScanRow is a wrapper around the dbscan.ScanRow function.
 // See dbscan.ScanRow for details.
 func (api *API) ScanRow(dst interface{}, rows pgx.Rows) error {
-	return api.dbscanAPI.ScanRow(dst, NewRowsAdapter(rows))
+	return api.dbscanAPI.ScanRow(dst, api.newRowsAdapter(rows))
 }
 
-// RowsAdapter makes pgx.Rows compliant with the dbscan.Rows interface.
+// RowsAdapter makes pgx.Rows compliant with the dbscan.Rows and dbscan.ColumnTypesRows interfaces.
 // See dbscan.Rows for details.
 type RowsAdapter struct {
 	pgx.Rows
+	uuidAsString bool
 }
 
 // NewRowsAdapter returns a new RowsAdapter instance.
@@ -180,6 +288,148 @@ func (ra RowsAdapter) Close() error {
 	return nil
 }
 
+// Scan implements the dbscan.Rows.Scan method.
+// Columns scanned into *interface{} destinations, like map[string]interface{} elements or interface fields,
+// get the values decoded according to the column type, the same way as pgx.Rows.Values does.
+// Columns scanned into *dbscan.Discard destinations aren't decoded at all,
+// the rest of the columns are scanned as usual.
+func (ra RowsAdapter) Scan(dest ...interface{}) error {
+	// pgx skips nil destinations, so they are used for columns that are handled here.
+	var rest []interface{}
+	var interfaceDest []int
+	for i, d := range dest {
+		switch d.(type) {
+		case *interface{}:
+			interfaceDest = append(interfaceDest, i)
+		case *dbscan.Discard:
+		default:
+			continue
+		}
+		if rest == nil {
+			rest = make([]interface{}, len(dest))
+			copy(rest, dest)
+		}
+		rest[i] = nil
+	}
+	if rest == nil {
+		return ra.Rows.Scan(dest...)
+	}
+	if err := ra.Rows.Scan(rest...); err != nil {
+		return err
+	}
+	if len(interfaceDest) > 0 {
+		return ra.scanInterfaceValues(dest, interfaceDest)
+	}
+	return nil
+}
+
+// scanInterfaceValues decodes values of the columns at the positions into *interface{} destinations.
+func (ra RowsAdapter) scanInterfaceValues(dest []interface{}, positions []int) error {
+	fieldDescriptions := ra.Rows.FieldDescriptions()
+	conn := ra.Rows.Conn()
+	if conn == nil {
+		// The type map is unknown, so only pgx can decode the values.
+		values, err := ra.Rows.Values()
+		if err != nil {
+			return fmt.Errorf("scany: get row values: %w", err)
+		}
+		for _, i := range positions {
+			*dest[i].(*interface{}) = ra.decodedValue(values[i], fieldDescriptions[i].DataTypeOID)
+		}
+		return nil
+	}
+	rawValues := ra.Rows.RawValues()
+	for _, i := range positions {
+		value, err := decodeValue(conn.TypeMap(), fieldDescriptions[i], rawValues[i])
+		if err != nil {
+			return fmt.Errorf("scany: decode value of column '%s': %w", fieldDescriptions[i].Name, err)
+		}
+		*dest[i].(*interface{}) = ra.decodedValue(value, fieldDescriptions[i].DataTypeOID)
+	}
+	return nil
+}
+
+// decodeValue decodes the raw value of the column the same way as pgx.Rows.Values does.
+func decodeValue(typeMap *pgtype.Map, fd pgconn.FieldDescription, raw []byte) (interface{}, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	dataType, ok := typeMap.TypeForOID(fd.DataTypeOID)
+	if !ok {
+		if fd.Format == pgx.TextFormatCode {
+			return string(raw), nil
+		}
+		return append([]byte(nil), raw...), nil
+	}
+	return dataType.Codec.DecodeValue(typeMap, fd.DataTypeOID, fd.Format, raw)
+}
+
+func (ra RowsAdapter) decodedValue(value interface{}, oid uint32) interface{} {
+	if !ra.uuidAsString {
+		return value
+	}
+	switch oid {
+	case pgtype.UUIDOID:
+		if uuid, ok := value.([16]byte); ok {
+			return formatUUID(uuid)
+		}
+	case pgtype.UUIDArrayOID:
+		if elements, ok := value.([]interface{}); ok {
+			for i, element := range elements {
+				if uuid, ok := element.([16]byte); ok {
+					elements[i] = formatUUID(uuid)
+				}
+			}
+		}
+	}
+	return value
+}
+
+// scanTypes maps OIDs of the built-in types to the Go types of their values.
+var scanTypes = map[uint32]reflect.Type{
+	pgtype.Int8OID:        reflect.TypeOf(int64(0)),
+	pgtype.Int4OID:        reflect.TypeOf(int32(0)),
+	pgtype.Int2OID:        reflect.TypeOf(int16(0)),
+	pgtype.Float8OID:      reflect.TypeOf(float64(0)),
+	pgtype.Float4OID:      reflect.TypeOf(float32(0)),
+	pgtype.BoolOID:        reflect.TypeOf(false),
+	pgtype.TextOID:        reflect.TypeOf(""),
+	pgtype.VarcharOID:     reflect.TypeOf(""),
+	pgtype.BPCharOID:      reflect.TypeOf(""),
+	pgtype.NameOID:        reflect.TypeOf(""),
+	pgtype.ByteaOID:       reflect.TypeOf([]byte(nil)),
+	pgtype.DateOID:        reflect.TypeOf(time.Time{}),
+	pgtype.TimestampOID:   reflect.TypeOf(time.Time{}),
+	pgtype.TimestamptzOID: reflect.TypeOf(time.Time{}),
+}
+
+// ColumnTypes implements dbscan.ColumnTypesRows interface.
+// Scan types are known only for the basic built-in types.
+// pgx doesn't report whether a column is nullable, so NullableKnown is always false
+// and dbscan.WithColumnTypeCheck can't detect nullable columns scanned into fields that can't hold NULL.
+func (ra RowsAdapter) ColumnTypes() ([]dbscan.ColumnType, error) {
+	var typeMap *pgtype.Map
+	if conn := ra.Rows.Conn(); conn != nil {
+		typeMap = conn.TypeMap()
+	}
+	fieldDescriptions := ra.Rows.FieldDescriptions()
+	result := make([]dbscan.ColumnType, len(fieldDescriptions))
+	for i, fd := range fieldDescriptions {
+		result[i] = dbscan.ColumnType{Name: fd.Name, ScanType: scanTypes[fd.DataTypeOID]}
+		if typeMap == nil {
+			continue
+		}
+		if dataType, ok := typeMap.TypeForOID(fd.DataTypeOID); ok {
+			result[i].DatabaseTypeName = strings.ToUpper(dataType.Name)
+		}
+	}
+	return result, nil
+}
+
+func formatUUID(uuid [16]byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16])
+}
+
 func mustNewDBScanAPI(opts ...dbscan.APIOption) *dbscan.API {
 	api, err := NewDBScanAPI(opts...)
 	if err != nil {
@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/georgysavva/scany/v2/dbscan"
 	"github.com/georgysavva/scany/v2/pgxscan"
 )
 
@@ -59,6 +60,27 @@ func TestSelect(t *testing.T) {
 	assert.Equal(t, expected, got)
 }
 
+func TestSelect_withScanOption_keyedMap(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT *
+		FROM (
+			VALUES ('foo val', 'bar val'), ('foo val', 'bar val 2'), ('foo val 3', 'bar val 3')
+		) AS t (foo, bar)
+		WHERE foo <> $1
+	`
+	expected := map[string][]string{
+		"foo val": {"bar val", "bar val 2"},
+	}
+
+	var got map[string][]string
+	opts := []dbscan.ScanOption{dbscan.WithKeyColumn("foo")}
+	err := testAPI.SelectWith(ctx, testDB, &got, query, opts, "foo val 3")
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
+
 func TestSelect_queryError_propagatesAndWrapsErr(t *testing.T) {
 	t.Parallel()
 	query := `
@@ -86,6 +108,22 @@ func TestGet(t *testing.T) {
 	assert.Equal(t, expected, got)
 }
 
+func TestGet_withScanOption_capture(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT 'foo val' AS foo, 3::INT8 AS total
+	`
+
+	var got string
+	var total int64
+	opts := []dbscan.ScanOption{dbscan.WithCapture("total", &total)}
+	err := testAPI.GetWith(ctx, testDB, &got, query, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo val", got)
+	assert.Equal(t, int64(3), total)
+}
+
 func TestGet_queryError_propagatesAndWrapsErr(t *testing.T) {
 	t.Parallel()
 	query := `
@@ -99,6 +137,25 @@ func TestGet_queryError_propagatesAndWrapsErr(t *testing.T) {
 	assert.EqualError(t, err, expectedErr)
 }
 
+func TestGetValues(t *testing.T) {
+	t.Parallel()
+	var foo, bar string
+	err := testAPI.GetValues(ctx, testDB, singleRowsQuery)(&foo, &bar)
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo val", foo)
+	assert.Equal(t, "bar val", bar)
+}
+
+func TestGetValues_noRows_returnsNotFoundErr(t *testing.T) {
+	t.Parallel()
+	var foo, bar *string
+	err := testAPI.GetValues(ctx, testDB, noRowsQuery)(&foo, &bar)
+
+	assert.True(t, pgxscan.NotFound(err))
+	assert.True(t, errors.Is(err, pgx.ErrNoRows))
+}
+
 func TestScanAll(t *testing.T) {
 	t.Parallel()
 	expected := []*testModel{
@@ -141,6 +198,59 @@ func TestScanOne_noRows_returnsNotFoundErr(t *testing.T) {
 	assert.True(t, errors.Is(err, pgx.ErrNoRows))
 }
 
+func TestScanOne_interfaceValues_decodedByPgx(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT 'foo val' AS foo, '00000000-0000-0000-0000-000000000001'::UUID AS bar, NULL AS baz
+	`
+	type dst struct {
+		Foo string
+		Bar interface{}
+		Baz interface{}
+	}
+	uuid := [16]byte{15: 1}
+	cases := []struct {
+		name     string
+		opts     []pgxscan.APIOption
+		dst      interface{}
+		expected interface{}
+	}{
+		{
+			name:     "map",
+			dst:      &map[string]interface{}{},
+			expected: &map[string]interface{}{"foo": "foo val", "bar": uuid, "baz": nil},
+		},
+		{
+			name:     "struct with interface fields",
+			dst:      &dst{},
+			expected: &dst{Foo: "foo val", Bar: uuid},
+		},
+		{
+			name: "uuid as string",
+			opts: []pgxscan.APIOption{pgxscan.WithUUIDAsString(true)},
+			dst:  &map[string]interface{}{},
+			expected: &map[string]interface{}{
+				"foo": "foo val", "bar": "00000000-0000-0000-0000-000000000001", "baz": nil,
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			api, err := getAPI(tc.opts...)
+			require.NoError(t, err)
+			rows, err := testDB.Query(ctx, query)
+			require.NoError(t, err)
+
+			err = api.ScanOne(tc.dst, rows)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, tc.dst)
+		})
+	}
+}
+
 func TestRowScanner_Scan(t *testing.T) {
 	t.Parallel()
 	rows, err := testDB.Query(ctx, singleRowsQuery)
@@ -214,12 +324,33 @@ func TestScanRow(t *testing.T) {
 	assert.Equal(t, expected, got)
 }
 
-func getAPI() (*pgxscan.API, error) {
+func TestRegisterNullScanPlan(t *testing.T) {
+	t.Parallel()
+	m := pgtype.NewMap()
+	pgxscan.RegisterNullScanPlan(m)
+
+	var num dbscan.Null[int64]
+	err := m.Scan(pgtype.Int8OID, pgtype.TextFormatCode, []byte("42"), &num)
+	require.NoError(t, err)
+	assert.Equal(t, dbscan.NewNull(int64(42)), num)
+
+	err = m.Scan(pgtype.Int8OID, pgtype.TextFormatCode, nil, &num)
+	require.NoError(t, err)
+	assert.Equal(t, dbscan.Null[int64]{}, num)
+
+	// Arrays aren't supported by the sql.Scanner implementation, they are decoded by the pgx codec.
+	var tags dbscan.Null[[]string]
+	err = m.Scan(pgtype.TextArrayOID, pgtype.TextFormatCode, []byte("{foo,bar}"), &tags)
+	require.NoError(t, err)
+	assert.Equal(t, dbscan.NewNull([]string{"foo", "bar"}), tags)
+}
+
+func getAPI(opts ...pgxscan.APIOption) (*pgxscan.API, error) {
 	dbscanAPI, err := pgxscan.NewDBScanAPI()
 	if err != nil {
 		return nil, fmt.Errorf("new DB scan API: %w", err)
 	}
-	api, err := pgxscan.NewAPI(dbscanAPI)
+	api, err := pgxscan.NewAPI(dbscanAPI, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("new API: %w", err)
 	}
@@ -14,5 +14,16 @@ sqlscan can query rows and work with *sql.DB, *sql.Conn or *sql.Tx directly.
 To support this it has two high-level functions Select and Get,
 they accept anything that implements Querier interface and query rows from it.
 This means that they can be used with *sql.DB, *sql.Conn or *sql.Tx.
+
+Dynamic results
+
+When columns are scanned into interface values, like map[string]interface{} elements,
+dbscan.Record values or []interface{}, they are whatever the driver returns by default.
+WithColumnScanTypes option makes sqlscan choose the Go type for every column
+from the column type metadata reported by the driver, see sql.Rows.ColumnTypes.
+Many drivers return []byte for textual columns, use WithTextBytesAsString option
+to get string values instead, columns of binary database types, like BLOB or BYTEA, are left as []byte:
+
+	api, err := sqlscan.NewAPI(dbscanAPI, sqlscan.WithColumnScanTypes(true), sqlscan.WithTextBytesAsString(true))
 */
 package sqlscan
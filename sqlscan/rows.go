@@ -0,0 +1,142 @@
+package sqlscan
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/georgysavva/scany/v2/dbscan"
+)
+
+// RowsAdapter makes *sql.Rows compliant with the dbscan.ValueTypesRows and dbscan.ColumnTypesRows interfaces.
+// It can choose Go types for columns scanned into interface values, like map[string]interface{} elements
+// or dbscan.Record values, from the column type metadata, see sql.Rows.ColumnTypes and WithColumnScanTypes.
+type RowsAdapter struct {
+	*sql.Rows
+	columnScanTypes   bool
+	textBytesAsString bool
+}
+
+var (
+	_ dbscan.ValueTypesRows  = &RowsAdapter{}
+	_ dbscan.ColumnTypesRows = &RowsAdapter{}
+)
+
+// NewRowsAdapter returns a new RowsAdapter instance.
+// Like the API, it doesn't report value types by default, see WithColumnScanTypes and WithTextBytesAsString.
+func NewRowsAdapter(rows *sql.Rows, opts ...APIOption) *RowsAdapter {
+	api := &API{}
+	for _, o := range opts {
+		o(api)
+	}
+	return api.newRowsAdapter(rows)
+}
+
+var (
+	bytesType  = reflect.TypeOf([]byte(nil))
+	stringType = reflect.TypeOf("")
+)
+
+// nullValueTypes maps database/sql Null types to the types of their values.
+var nullValueTypes = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(sql.NullString{}):  stringType,
+	reflect.TypeOf(sql.NullInt64{}):   reflect.TypeOf(int64(0)),
+	reflect.TypeOf(sql.NullInt32{}):   reflect.TypeOf(int32(0)),
+	reflect.TypeOf(sql.NullInt16{}):   reflect.TypeOf(int16(0)),
+	reflect.TypeOf(sql.NullByte{}):    reflect.TypeOf(byte(0)),
+	reflect.TypeOf(sql.NullFloat64{}): reflect.TypeOf(float64(0)),
+	reflect.TypeOf(sql.NullBool{}):    reflect.TypeOf(false),
+	reflect.TypeOf(sql.NullTime{}):    reflect.TypeOf(sql.NullTime{}.Time),
+	reflect.TypeOf(sql.RawBytes{}):    bytesType,
+}
+
+// binaryTypeNames contains parts of database type names that hold binary data rather than text.
+var binaryTypeNames = []string{"BLOB", "BINARY", "BYTEA", "IMAGE"}
+
+// decimalTypeNames contains parts of database type names of exact numbers,
+// that don't fit into float types reported by some drivers.
+var decimalTypeNames = []string{"NUMERIC", "DECIMAL", "MONEY"}
+
+// ValueTypes implements dbscan.ValueTypesRows interface.
+// If column scan types are enabled, the type of a column is the scan type reported by the driver,
+// database/sql Null types and sql.RawBytes are replaced with the types of their values.
+// Otherwise the only reported type is string for textual columns with []byte values, see WithTextBytesAsString,
+// other values are left as the driver returns them.
+func (ra *RowsAdapter) ValueTypes() ([]reflect.Type, error) {
+	if !ra.columnScanTypes && !ra.textBytesAsString {
+		// Column types aren't needed, don't ask the driver for them.
+		columns, err := ra.Rows.Columns()
+		if err != nil {
+			return nil, fmt.Errorf("scany: get rows columns: %w", err)
+		}
+		return make([]reflect.Type, len(columns)), nil
+	}
+	columnTypes, err := ra.Rows.ColumnTypes()
+	if err != nil {
+		return nil, fmt.Errorf("scany: get rows column types: %w", err)
+	}
+	valueTypes := make([]reflect.Type, len(columnTypes))
+	for i, ct := range columnTypes {
+		vt := valueType(ct)
+		if vt == bytesType && ra.textBytesAsString && !isBinaryType(ct.DatabaseTypeName()) {
+			valueTypes[i] = stringType
+		} else if ra.columnScanTypes {
+			valueTypes[i] = vt
+		}
+	}
+	return valueTypes, nil
+}
+
+// ColumnTypes implements dbscan.ColumnTypesRows interface.
+// It shadows sql.Rows.ColumnTypes method, that is still available as RowsAdapter.Rows.ColumnTypes.
+func (ra *RowsAdapter) ColumnTypes() ([]dbscan.ColumnType, error) {
+	columnTypes, err := ra.Rows.ColumnTypes()
+	if err != nil {
+		return nil, fmt.Errorf("scany: get rows column types: %w", err)
+	}
+	result := make([]dbscan.ColumnType, len(columnTypes))
+	for i, ct := range columnTypes {
+		nullable, nullableKnown := ct.Nullable()
+		result[i] = dbscan.ColumnType{
+			Name:             ct.Name(),
+			ScanType:         valueType(ct),
+			DatabaseTypeName: ct.DatabaseTypeName(),
+			Nullable:         nullable,
+			NullableKnown:    nullableKnown,
+		}
+	}
+	return result, nil
+}
+
+// valueType returns the type of the column values, it's nil if the driver doesn't report a concrete type.
+// Float scan types of exact numeric columns are dropped, since converting the values into floats loses precision.
+func valueType(ct *sql.ColumnType) reflect.Type {
+	scanType := ct.ScanType()
+	if scanType == nil || scanType.Kind() == reflect.Interface {
+		return nil
+	}
+	if valueType, ok := nullValueTypes[scanType]; ok {
+		scanType = valueType
+	}
+	isFloat := scanType.Kind() == reflect.Float32 || scanType.Kind() == reflect.Float64
+	if isFloat && containsTypeName(ct.DatabaseTypeName(), decimalTypeNames) {
+		return nil
+	}
+	return scanType
+}
+
+func isBinaryType(databaseTypeName string) bool {
+	return containsTypeName(databaseTypeName, binaryTypeNames)
+}
+
+// containsTypeName reports whether the database type name contains any of the parts.
+func containsTypeName(databaseTypeName string, parts []string) bool {
+	name := strings.ToUpper(databaseTypeName)
+	for _, part := range parts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
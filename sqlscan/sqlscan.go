@@ -27,22 +27,51 @@ func Select(ctx context.Context, db Querier, dst interface{}, query string, args
 	return DefaultAPI.Select(ctx, db, dst, query, args...)
 }
 
+// SelectWith is a package-level helper function that uses the DefaultAPI object.
+// See API.SelectWith for details.
+func SelectWith(ctx context.Context, db Querier, dst interface{}, query string,
+	opts []dbscan.ScanOption, args ...interface{},
+) error {
+	return DefaultAPI.SelectWith(ctx, db, dst, query, opts, args...)
+}
+
 // Get is a package-level helper function that uses the DefaultAPI object.
 // See API.Get for details.
 func Get(ctx context.Context, db Querier, dst interface{}, query string, args ...interface{}) error {
 	return DefaultAPI.Get(ctx, db, dst, query, args...)
 }
 
+// GetWith is a package-level helper function that uses the DefaultAPI object.
+// See API.GetWith for details.
+func GetWith(ctx context.Context, db Querier, dst interface{}, query string,
+	opts []dbscan.ScanOption, args ...interface{},
+) error {
+	return DefaultAPI.GetWith(ctx, db, dst, query, opts, args...)
+}
+
+// GetValues is a package-level helper function that uses the DefaultAPI object.
+// See API.GetValues for details.
+func GetValues(ctx context.Context, db Querier, query string, args ...interface{},
+) func(dst ...interface{}) error {
+	return DefaultAPI.GetValues(ctx, db, query, args...)
+}
+
 // ScanAll is a package-level helper function that uses the DefaultAPI object.
 // See API.ScanAll for details.
-func ScanAll(dst interface{}, rows *sql.Rows) error {
-	return DefaultAPI.ScanAll(dst, rows)
+func ScanAll(dst interface{}, rows *sql.Rows, opts ...dbscan.ScanOption) error {
+	return DefaultAPI.ScanAll(dst, rows, opts...)
+}
+
+// ScanOneValues is a package-level helper function that uses the DefaultAPI object.
+// See API.ScanOneValues for details.
+func ScanOneValues(rows *sql.Rows, dst ...interface{}) error {
+	return DefaultAPI.ScanOneValues(rows, dst...)
 }
 
 // ScanOne is a package-level helper function that uses the DefaultAPI object.
 // See API.ScanOne for details.
-func ScanOne(dst interface{}, rows *sql.Rows) error {
-	return DefaultAPI.ScanOne(dst, rows)
+func ScanOne(dst interface{}, rows *sql.Rows, opts ...dbscan.ScanOption) error {
+	return DefaultAPI.ScanOne(dst, rows, opts...)
 }
 
 // RowScanner is a wrapper around the dbscan.RowScanner type.
@@ -78,23 +107,66 @@ func NewDBScanAPI(opts ...dbscan.APIOption) (*dbscan.API, error) {
 // API is a wrapper around the dbscan.API type.
 // See dbscan.API for details.
 type API struct {
-	dbscanAPI *dbscan.API
+	dbscanAPI         *dbscan.API
+	columnScanTypes   bool
+	textBytesAsString bool
+}
+
+// APIOption is a function type that changes API configuration.
+type APIOption func(api *API)
+
+// WithColumnScanTypes makes sqlscan choose the Go type for columns scanned into interface values,
+// like map[string]interface{} elements or dbscan.Record values, from the scan type reported by the driver,
+// see sql.ColumnType.ScanType. For example, an INT4 column becomes int32 instead of int64 for some drivers.
+// Exact numeric columns, like NUMERIC or DECIMAL, are left as the driver returns them,
+// even if the driver reports a float scan type for them.
+// It's disabled by default, so interface values are whatever the driver returns.
+func WithColumnScanTypes(enabled bool) APIOption {
+	return func(api *API) {
+		api.columnScanTypes = enabled
+	}
+}
+
+// WithTextBytesAsString makes sqlscan convert []byte values into string
+// for columns scanned into interface values, like map[string]interface{} elements or dbscan.Record values,
+// unless the database type of the column is binary, e.g. BLOB or BYTEA.
+// Many drivers return []byte for textual columns, that are encoded to base64 in JSON.
+func WithTextBytesAsString(enabled bool) APIOption {
+	return func(api *API) {
+		api.textBytesAsString = enabled
+	}
 }
 
 // NewAPI creates new API instance from dbscan.API instance.
-func NewAPI(dbscanAPI *dbscan.API) (*API, error) {
+func NewAPI(dbscanAPI *dbscan.API, opts ...APIOption) (*API, error) {
 	api := &API{dbscanAPI: dbscanAPI}
+	for _, o := range opts {
+		o(api)
+	}
 	return api, nil
 }
 
+func (api *API) newRowsAdapter(rows *sql.Rows) *RowsAdapter {
+	return &RowsAdapter{Rows: rows, columnScanTypes: api.columnScanTypes, textBytesAsString: api.textBytesAsString}
+}
+
 // Select is a high-level function that queries rows from Querier and calls the ScanAll function.
 // See ScanAll for details.
 func (api *API) Select(ctx context.Context, db Querier, dst interface{}, query string, args ...interface{}) error {
+	return api.SelectWith(ctx, db, dst, query, nil, args...)
+}
+
+// SelectWith is the same as Select, but it passes the scan options to the ScanAll function:
+//
+//	err := SelectWith(ctx, db, &ordersByUser, query, []dbscan.ScanOption{dbscan.WithKeyColumn("user_id")}, since)
+func (api *API) SelectWith(ctx context.Context, db Querier, dst interface{}, query string,
+	opts []dbscan.ScanOption, args ...interface{},
+) error {
 	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("scany: query multiple result rows: %w", err)
 	}
-	if err := api.ScanAll(dst, rows); err != nil {
+	if err := api.ScanAll(dst, rows, opts...); err != nil {
 		return fmt.Errorf("scanning all: %w", err)
 	}
 	return nil
@@ -103,27 +175,71 @@ func (api *API) Select(ctx context.Context, db Querier, dst interface{}, query s
 // Get is a high-level function that queries rows from Querier and calls the ScanOne function.
 // See ScanOne for details.
 func (api *API) Get(ctx context.Context, db Querier, dst interface{}, query string, args ...interface{}) error {
+	return api.GetWith(ctx, db, dst, query, nil, args...)
+}
+
+// GetWith is the same as Get, but it passes the scan options to the ScanOne function:
+//
+//	err := GetWith(ctx, db, &user, query, []dbscan.ScanOption{dbscan.WithSparseFields(true)}, id)
+func (api *API) GetWith(ctx context.Context, db Querier, dst interface{}, query string,
+	opts []dbscan.ScanOption, args ...interface{},
+) error {
 	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("scany: query one result row: %w", err)
 	}
-	if err := api.ScanOne(dst, rows); err != nil {
+	if err := api.ScanOne(dst, rows, opts...); err != nil {
 		return fmt.Errorf("scanning one: %w", err)
 	}
 	return nil
 }
 
+// GetValues is the same as Get, but it scans the single row into multiple destinations by position:
+//
+//	var count int
+//	var maxAge *int
+//	err := GetValues(ctx, db, "SELECT count(*), max(age) FROM users")(&count, &maxAge)
+//
+// The query is executed when the returned function is called. See ScanOneValues for details.
+func (api *API) GetValues(ctx context.Context, db Querier, query string, args ...interface{},
+) func(dst ...interface{}) error {
+	return func(dst ...interface{}) error {
+		rows, err := db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("scany: query one result row: %w", err)
+		}
+		if err := api.ScanOneValues(rows, dst...); err != nil {
+			return fmt.Errorf("scanning one: %w", err)
+		}
+		return nil
+	}
+}
+
 // ScanAll is a wrapper around the dbscan.ScanAll function.
 // See dbscan.ScanAll for details.
-func (api *API) ScanAll(dst interface{}, rows *sql.Rows) error {
-	return api.dbscanAPI.ScanAll(dst, rows)
+func (api *API) ScanAll(dst interface{}, rows *sql.Rows, opts ...dbscan.ScanOption) error {
+	return api.dbscanAPI.ScanAll(dst, api.newRowsAdapter(rows), opts...)
 }
 
 // ScanOne is a wrapper around the dbscan.ScanOne function.
 // See dbscan.ScanOne for details. If no rows are found it
 // returns an sql.ErrNoRows error.
-func (api *API) ScanOne(dst interface{}, rows *sql.Rows) error {
-	switch err := api.dbscanAPI.ScanOne(dst, rows); {
+func (api *API) ScanOne(dst interface{}, rows *sql.Rows, opts ...dbscan.ScanOption) error {
+	switch err := api.dbscanAPI.ScanOne(dst, api.newRowsAdapter(rows), opts...); {
+	case dbscan.NotFound(err):
+		return fmt.Errorf("%w", sql.ErrNoRows)
+	case err != nil:
+		return fmt.Errorf("%w", err)
+	default:
+		return nil
+	}
+}
+
+// ScanOneValues is a wrapper around the dbscan.ScanOneValues function.
+// See dbscan.ScanOneValues for details. If no rows are found it
+// returns an sql.ErrNoRows error.
+func (api *API) ScanOneValues(rows *sql.Rows, dst ...interface{}) error {
+	switch err := api.dbscanAPI.ScanOneValues(api.newRowsAdapter(rows), dst...); {
 	case dbscan.NotFound(err):
 		return fmt.Errorf("%w", sql.ErrNoRows)
 	case err != nil:
@@ -141,13 +257,13 @@ func NotFound(err error) bool {
 
 // NewRowScanner returns a new RowScanner instance.
 func (api *API) NewRowScanner(rows *sql.Rows) *RowScanner {
-	return &RowScanner{RowScanner: api.dbscanAPI.NewRowScanner(rows)}
+	return &RowScanner{RowScanner: api.dbscanAPI.NewRowScanner(api.newRowsAdapter(rows))}
 }
 
 // ScanRow is a wrapper around the dbscan.ScanRow function.
 // See dbscan.ScanRow for details.
 func (api *API) ScanRow(dst interface{}, rows *sql.Rows) error {
-	return api.dbscanAPI.ScanRow(dst, rows)
+	return api.dbscanAPI.ScanRow(dst, api.newRowsAdapter(rows))
 }
 
 func mustNewDBScanAPI(opts ...dbscan.APIOption) *dbscan.API {
@@ -7,6 +7,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"reflect"
 	"testing"
 
 	"github.com/cockroachdb/cockroach-go/v2/testserver"
@@ -14,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/georgysavva/scany/v2/dbscan"
 	"github.com/georgysavva/scany/v2/sqlscan"
 )
 
@@ -58,6 +60,27 @@ func TestSelect(t *testing.T) {
 	assert.Equal(t, expected, got)
 }
 
+func TestSelect_withScanOption_keyedMap(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT *
+		FROM (
+			VALUES ('foo val', 'bar val'), ('foo val', 'bar val 2'), ('foo val 3', 'bar val 3')
+		) AS t (foo, bar)
+		WHERE foo <> $1
+	`
+	expected := map[string][]string{
+		"foo val": {"bar val", "bar val 2"},
+	}
+
+	var got map[string][]string
+	opts := []dbscan.ScanOption{dbscan.WithKeyColumn("foo")}
+	err := testAPI.SelectWith(ctx, testDB, &got, query, opts, "foo val 3")
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
+
 func TestSelect_queryError_propagatesAndWrapsErr(t *testing.T) {
 	t.Parallel()
 	query := `
@@ -85,6 +108,22 @@ func TestGet(t *testing.T) {
 	assert.Equal(t, expected, got)
 }
 
+func TestGet_withScanOption_capture(t *testing.T) {
+	t.Parallel()
+	query := `
+		SELECT 'foo val' AS foo, 3::INT8 AS total
+	`
+
+	var got string
+	var total int64
+	opts := []dbscan.ScanOption{dbscan.WithCapture("total", &total)}
+	err := testAPI.GetWith(ctx, testDB, &got, query, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo val", got)
+	assert.Equal(t, int64(3), total)
+}
+
 func TestGet_queryError_propagatesAndWrapsErr(t *testing.T) {
 	t.Parallel()
 	query := `
@@ -98,6 +137,25 @@ func TestGet_queryError_propagatesAndWrapsErr(t *testing.T) {
 	assert.EqualError(t, err, expectedErr)
 }
 
+func TestGetValues(t *testing.T) {
+	t.Parallel()
+	var foo, bar string
+	err := testAPI.GetValues(ctx, testDB, singleRowsQuery)(&foo, &bar)
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo val", foo)
+	assert.Equal(t, "bar val", bar)
+}
+
+func TestGetValues_noRows_returnsNotFoundErr(t *testing.T) {
+	t.Parallel()
+	var foo, bar *string
+	err := testAPI.GetValues(ctx, testDB, noRowsQuery)(&foo, &bar)
+
+	assert.True(t, sqlscan.NotFound(err))
+	assert.True(t, errors.Is(err, sql.ErrNoRows))
+}
+
 func TestScanAll(t *testing.T) {
 	t.Parallel()
 	expected := []*testModel{
@@ -173,6 +231,91 @@ func TestScanRow(t *testing.T) {
 	assert.Equal(t, expected, got)
 }
 
+func TestScanOne_interfaceMap_withColumnScanTypes_usesColumnTypes(t *testing.T) {
+	t.Parallel()
+	api, err := getAPI(sqlscan.WithColumnScanTypes(true))
+	require.NoError(t, err)
+	rows, err := testDB.Query(`SELECT 1::INT4 AS foo, 'bar val' AS bar, NULL::INT8 AS baz, 1.10::NUMERIC AS qux`)
+	require.NoError(t, err)
+
+	var got map[string]interface{}
+	err = api.ScanOne(&got, rows)
+	require.NoError(t, err)
+
+	// NUMERIC values aren't converted into float64, they are returned by the driver as is.
+	expected := map[string]interface{}{"foo": int32(1), "bar": "bar val", "baz": nil, "qux": "1.10"}
+	assert.Equal(t, expected, got)
+}
+
+func TestScanOne_interfaceMap_valuesReturnedByDriver(t *testing.T) {
+	t.Parallel()
+	rows, err := testDB.Query(`SELECT 1::INT4 AS foo, 'bar val' AS bar, NULL::INT8 AS baz`)
+	require.NoError(t, err)
+
+	var got map[string]interface{}
+	err = testAPI.ScanOne(&got, rows)
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{"foo": int64(1), "bar": "bar val", "baz": nil}
+	assert.Equal(t, expected, got)
+}
+
+func TestRowsAdapter_ValueTypes(t *testing.T) {
+	t.Parallel()
+	rows, err := testDB.Query(`SELECT 1::INT8 AS foo, 'bar val' AS bar, 'baz val'::BYTEA AS baz`)
+	require.NoError(t, err)
+	defer rows.Close() //nolint: errcheck
+
+	got, err := sqlscan.NewRowsAdapter(rows, sqlscan.WithColumnScanTypes(true)).ValueTypes()
+	require.NoError(t, err)
+
+	expected := []reflect.Type{reflect.TypeOf(int64(0)), reflect.TypeOf(""), reflect.TypeOf([]byte(nil))}
+	assert.Equal(t, expected, got)
+}
+
+func TestRowsAdapter_ValueTypes_disabledByDefault(t *testing.T) {
+	t.Parallel()
+	rows, err := testDB.Query(`SELECT 1::INT8 AS foo, 'bar val' AS bar`)
+	require.NoError(t, err)
+	defer rows.Close() //nolint: errcheck
+
+	got, err := sqlscan.NewRowsAdapter(rows).ValueTypes()
+	require.NoError(t, err)
+
+	assert.Equal(t, []reflect.Type{nil, nil}, got)
+}
+
+func TestRowsAdapter_ColumnTypes(t *testing.T) {
+	t.Parallel()
+	rows, err := testDB.Query(`SELECT 1::INT8 AS foo, 'bar val'::TEXT AS bar`)
+	require.NoError(t, err)
+	defer rows.Close() //nolint: errcheck
+
+	got, err := sqlscan.NewRowsAdapter(rows).ColumnTypes()
+	require.NoError(t, err)
+
+	expected := []dbscan.ColumnType{
+		{Name: "foo", ScanType: reflect.TypeOf(int64(0)), DatabaseTypeName: "INT8"},
+		{Name: "bar", ScanType: reflect.TypeOf(""), DatabaseTypeName: "TEXT"},
+	}
+	assert.Equal(t, expected, got)
+}
+
+func TestScanOne_withTextBytesAsString_binaryColumnStaysBytes(t *testing.T) {
+	t.Parallel()
+	api, err := getAPI(sqlscan.WithTextBytesAsString(true))
+	require.NoError(t, err)
+	rows, err := testDB.Query(`SELECT 'foo val' AS foo, 'bar val'::BYTEA AS bar`)
+	require.NoError(t, err)
+
+	var got map[string]interface{}
+	err = api.ScanOne(&got, rows)
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{"foo": "foo val", "bar": []byte("bar val")}
+	assert.Equal(t, expected, got)
+}
+
 func TestRowScanner_Scan_closedRows(t *testing.T) {
 	t.Parallel()
 	rows, err := testDB.Query(multipleRowsQuery)
@@ -249,12 +392,12 @@ func requireNoRowsErrorsAndClose(t *testing.T, rows *sql.Rows) {
 	require.NoError(t, rows.Close())
 }
 
-func getAPI() (*sqlscan.API, error) {
+func getAPI(opts ...sqlscan.APIOption) (*sqlscan.API, error) {
 	dbscanAPI, err := sqlscan.NewDBScanAPI()
 	if err != nil {
 		return nil, fmt.Errorf("new DB scan API: %w", err)
 	}
-	api, err := sqlscan.NewAPI(dbscanAPI)
+	api, err := sqlscan.NewAPI(dbscanAPI, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("new API: %w", err)
 	}